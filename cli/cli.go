@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/http"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	ExitOK = iota
+	ExitFailure
+	ExitUsage
+)
+
+var errUsage = errors.New("usage")
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, args []string) error
+	// Offline commands run before the config is loaded
+	offline bool
+	// Anonymous commands don't need a secret key
+	anonymous bool
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"list":           {"list [--output table|json|ndjson|csv]", runList, false, false},
+		"add":            {"add <magnet|file> --save-path <path>", runAdd, false, false},
+		"remove":         {"remove <hash> [--delete-data]", runRemove, false, false},
+		"pause":          {"pause <hash>", runPause, false, false},
+		"resume":         {"resume <hash>", runResume, false, false},
+		"move":           {"move <hash> <path>", runMove, false, false},
+		"props":          {"props get <hash> | props set <hash> [--download-limit n] [--upload-limit n] [--max-connections n] [--max-uploads n] [--auto-managed=bool] [--sequential=bool]", runProps, false, false},
+		"help":           {"help", runHelp, true, false},
+		"encrypt-secret": {"encrypt-secret", runEncryptSecret, true, false},
+		"login":          {"login [--username name]", runLogin, false, true},
+	}
+}
+
+// IsCommand reports whether name is a subcommand.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// IsOfflineCommand reports whether the subcommand works without a config
+// file or a connection to Porla.
+func IsOfflineCommand(name string) bool {
+	return commands[name].offline
+}
+
+// NeedsSecretKey reports whether the subcommand needs the secret key of the
+// current profile.
+func NeedsSecretKey(name string) bool {
+	return !commands[name].offline && !commands[name].anonymous
+}
+
+// Run executes the subcommand in args[0] and returns the process exit code.
+// Cancelling ctx aborts the calls to Porla it makes.
+func Run(ctx context.Context, args []string) int {
+	c, ok := commands[args[0]]
+	if !ok {
+		printUsage(os.Stderr)
+		return ExitUsage
+	}
+	err := c.run(ctx, args[1:])
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		fmt.Fprintln(os.Stderr, "usage: osprey "+c.usage)
+		return ExitUsage
+	case http.IsUnauthorized(err):
+		fmt.Fprintln(os.Stderr, "osprey: Porla rejected the secret key, run osprey login to get a new one")
+		return ExitFailure
+	}
+	fmt.Fprintln(os.Stderr, "osprey: "+err.Error())
+	return ExitFailure
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: osprey [command]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command the interactive interface is started. Commands:")
+	for _, name := range []string{"list", "add", "remove", "pause", "resume", "move", "props", "login", "encrypt-secret", "help"} {
+		fmt.Fprintln(w, "  osprey "+commands[name].usage)
+	}
+}
+
+func runHelp(ctx context.Context, args []string) error {
+	printUsage(os.Stdout)
+	return nil
+}
+
+// The flag package stops at the first positional argument, this lets flags
+// appear anywhere on the command line.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// findTorrent resolves a full or abbreviated v1 or v2 info hash. Hybrid
+// torrents must be sent to Porla with both hashes, so they are looked up in
+// the torrent list rather than parsed from the argument.
+func findTorrent(ctx context.Context, hash string) (torrents.Torrent, error) {
+	hash = strings.ToLower(hash)
+	all, err := http.Default.GetAllTorrents(ctx)
+	if err != nil {
+		return torrents.Torrent{}, err
+	}
+	var matches []torrents.Torrent
+	for _, torrent := range all {
+		for _, h := range torrent.InfoHash {
+			if h != "" && strings.HasPrefix(h, hash) {
+				matches = append(matches, torrent)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return torrents.Torrent{}, fmt.Errorf("no torrent matches %q", hash)
+	case 1:
+		return matches[0], nil
+	}
+	return torrents.Torrent{}, fmt.Errorf("%q matches %d torrents", hash, len(matches))
+}
+
+func runList(ctx context.Context, args []string) error {
+	fs := newFlagSet("list")
+	output := fs.String("output", OutputTable, "")
+	fs.StringVar(output, "o", OutputTable, "")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || !isOutputFormat(*output) {
+		return errUsage
+	}
+	all, err := http.Default.GetAllTorrents(ctx)
+	if err != nil {
+		return err
+	}
+	return writeTorrents(os.Stdout, *output, all, config.Currenti18n)
+}
+
+func runAdd(ctx context.Context, args []string) error {
+	fs := newFlagSet("add")
+	savePath := fs.String("save-path", "", "")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *savePath == "" {
+		return errUsage
+	}
+	return http.Default.AddTorrent(ctx, positional[0], *savePath, strings.HasPrefix(positional[0], "magnet:"))
+}
+
+func runRemove(ctx context.Context, args []string) error {
+	fs := newFlagSet("remove")
+	deleteData := fs.Bool("delete-data", false, "")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+	torrent, err := findTorrent(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return http.Default.DeleteTorrent(ctx, torrent, !*deleteData)
+}
+
+func runPause(ctx context.Context, args []string) error {
+	return runOnTorrent(ctx, "pause", args, http.Default.PauseTorrent)
+}
+
+func runResume(ctx context.Context, args []string) error {
+	return runOnTorrent(ctx, "resume", args, http.Default.ResumeTorrent)
+}
+
+func runOnTorrent(ctx context.Context, name string, args []string, action func(context.Context, torrents.Torrent) error) error {
+	positional, err := parseInterspersed(newFlagSet(name), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+	torrent, err := findTorrent(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return action(ctx, torrent)
+}
+
+func runMove(ctx context.Context, args []string) error {
+	positional, err := parseInterspersed(newFlagSet("move"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return errUsage
+	}
+	torrent, err := findTorrent(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return http.Default.MoveTorrent(ctx, torrent, positional[1])
+}
+
+func runProps(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "get":
+		return runPropsGet(ctx, args[1:])
+	case "set":
+		return runPropsSet(ctx, args[1:])
+	}
+	return errUsage
+}
+
+func runPropsGet(ctx context.Context, args []string) error {
+	positional, err := parseInterspersed(newFlagSet("props get"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+	torrent, err := findTorrent(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	torrentProperties, err := http.Default.GetTorrentProperties(ctx, torrent)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "download_limit\t%d\n", torrentProperties.DownloadLimit)
+	fmt.Fprintf(w, "upload_limit\t%d\n", torrentProperties.UploadLimit)
+	fmt.Fprintf(w, "max_connections\t%d\n", torrentProperties.MaxConnections)
+	fmt.Fprintf(w, "max_uploads\t%d\n", torrentProperties.MaxUploads)
+	fmt.Fprintf(w, "auto_managed\t%t\n", torrents.IsAutoManaged(torrentProperties.Flags))
+	fmt.Fprintf(w, "sequential\t%t\n", torrents.IsSequenciallyDownloading(torrentProperties.Flags))
+	return w.Flush()
+}
+
+func runPropsSet(ctx context.Context, args []string) error {
+	fs := newFlagSet("props set")
+	downloadLimit := fs.String("download-limit", "", "")
+	uploadLimit := fs.String("upload-limit", "", "")
+	maxConnections := fs.String("max-connections", "", "")
+	maxUploads := fs.String("max-uploads", "", "")
+	autoManaged := fs.Bool("auto-managed", false, "")
+	sequential := fs.Bool("sequential", false, "")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+	torrent, err := findTorrent(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	// Both flags are always sent to Porla, keep the current value of the ones
+	// that weren't given.
+	torrentProperties, err := http.Default.GetTorrentProperties(ctx, torrent)
+	if err != nil {
+		return err
+	}
+	torrentPropertiesSetData := torrents.TorrentPropertiesSetData{
+		IsAutomaticallyManaged:    torrents.IsAutoManaged(torrentProperties.Flags),
+		IsSequenciallyDownloading: torrents.IsSequenciallyDownloading(torrentProperties.Flags),
+		DownloadLimit:             *downloadLimit,
+		MaxConnections:            *maxConnections,
+		MaxUploads:                *maxUploads,
+		UploadLimit:               *uploadLimit,
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "auto-managed":
+			torrentPropertiesSetData.IsAutomaticallyManaged = *autoManaged
+		case "sequential":
+			torrentPropertiesSetData.IsSequenciallyDownloading = *sequential
+		}
+	})
+	return http.Default.SetTorrentProperties(ctx, torrent, torrentPropertiesSetData)
+}
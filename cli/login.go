@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"osprey/config"
+	"osprey/http"
+	"strings"
+)
+
+func runLogin(ctx context.Context, args []string) error {
+	fs := newFlagSet("login")
+	username := fs.String("username", "", "")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return errUsage
+	}
+	if *username == "" {
+		fmt.Fprint(os.Stderr, "Username: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return err
+		}
+		*username = strings.TrimSpace(line)
+	}
+	password, err := ReadPassword("Password: ")
+	if err != nil {
+		return err
+	}
+
+	token, err := http.Default.Login(ctx, *username, password)
+	if http.IsUnauthorized(err) {
+		return errors.New("wrong username or password")
+	}
+	if err != nil {
+		return err
+	}
+	err = config.StoreSecretKey(config.Current.Name, token)
+	if errors.Is(err, config.ErrSecretKeyReadOnly) {
+		// Let the user put it where it belongs
+		fmt.Fprintln(os.Stderr, "osprey: "+err.Error()+", the new token is:")
+		fmt.Println(token)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Logged in to %s as %s\n", config.Current.JSONRPCEndpointURL, *username)
+	return nil
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"osprey/data/torrents"
+	"osprey/i18n"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	OutputTable  = "table"
+	OutputJSON   = "json"
+	OutputNDJSON = "ndjson"
+	OutputCSV    = "csv"
+)
+
+var outputFormats = []string{OutputTable, OutputJSON, OutputNDJSON, OutputCSV}
+
+// torrentRecord adds the values osprey derives from the flags and state of a
+// torrent, so scripts don't have to decode them again.
+type torrentRecord struct {
+	torrents.Torrent
+	StateString     string  `json:"state_string"`
+	Paused          bool    `json:"paused"`
+	AutoManaged     bool    `json:"auto_managed"`
+	ProgressPercent float64 `json:"progress_percent"`
+	ETA             *int64  `json:"eta"`
+}
+
+type torrentListRecord struct {
+	TorrentsTotal int             `json:"torrents_total"`
+	Torrents      []torrentRecord `json:"torrents"`
+}
+
+type column struct {
+	name  string
+	value func(torrentRecord) string
+}
+
+var csvColumns = []column{
+	{"info_hash_v1", func(r torrentRecord) string { return r.InfoHash[0] }},
+	{"info_hash_v2", func(r torrentRecord) string { return r.InfoHash[1] }},
+	{"name", func(r torrentRecord) string { return r.Name }},
+	{"state", func(r torrentRecord) string { return strconv.FormatUint(uint64(r.State), 10) }},
+	{"state_string", func(r torrentRecord) string { return r.StateString }},
+	{"error", func(r torrentRecord) string { return strconv.FormatBool(r.Error) }},
+	{"paused", func(r torrentRecord) string { return strconv.FormatBool(r.Paused) }},
+	{"auto_managed", func(r torrentRecord) string { return strconv.FormatBool(r.AutoManaged) }},
+	{"progress_percent", func(r torrentRecord) string { return strconv.FormatFloat(r.ProgressPercent, 'f', 2, 64) }},
+	{"eta", func(r torrentRecord) string { return formatETASeconds(r.ETA) }},
+	{"size", func(r torrentRecord) string { return strconv.FormatUint(r.Size, 10) }},
+	{"total", func(r torrentRecord) string { return strconv.FormatUint(r.Total, 10) }},
+	{"total_done", func(r torrentRecord) string { return strconv.FormatUint(r.TotalDone, 10) }},
+	{"download_rate", func(r torrentRecord) string { return strconv.FormatUint(r.DownloadRate, 10) }},
+	{"upload_rate", func(r torrentRecord) string { return strconv.FormatUint(r.UploadRate, 10) }},
+	{"num_peers", func(r torrentRecord) string { return strconv.FormatUint(r.NumPeers, 10) }},
+	{"num_seeds", func(r torrentRecord) string { return strconv.FormatUint(r.NumSeeds, 10) }},
+	{"list_peers", func(r torrentRecord) string { return strconv.FormatUint(r.ListPeers, 10) }},
+	{"list_seeds", func(r torrentRecord) string { return strconv.FormatUint(r.ListSeeds, 10) }},
+	{"queue_position", func(r torrentRecord) string { return strconv.FormatInt(r.QueuePosition, 10) }},
+	{"save_path", func(r torrentRecord) string { return r.SavePath }},
+	{"flags", func(r torrentRecord) string { return strconv.FormatUint(r.Flags, 10) }},
+}
+
+var tableColumns = []column{
+	{"HASH", func(r torrentRecord) string { return r.InfoHash.String() }},
+	{"STATE", func(r torrentRecord) string { return r.StateString }},
+	{"PROGRESS", func(r torrentRecord) string { return strconv.FormatFloat(r.ProgressPercent, 'f', 1, 64) + "%" }},
+	{"SIZE", func(r torrentRecord) string { return humanize.Bytes(r.Size) }},
+	{"DOWN", func(r torrentRecord) string { return humanize.Bytes(r.DownloadRate) + "/s" }},
+	{"UP", func(r torrentRecord) string { return humanize.Bytes(r.UploadRate) + "/s" }},
+	{"PEERS", func(r torrentRecord) string { return strconv.FormatUint(r.NumPeers, 10) }},
+	{"SEEDS", func(r torrentRecord) string { return strconv.FormatUint(r.NumSeeds, 10) }},
+	{"ETA", func(r torrentRecord) string { return formatETA(r.ETA) }},
+	{"NAME", func(r torrentRecord) string { return r.Name }},
+}
+
+func isOutputFormat(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Machine readable formats always use the English state names so their output
+// doesn't depend on the configured language, the table is meant for humans.
+func newTorrentRecord(torrent torrents.Torrent, language i18n.I18n) torrentRecord {
+	record := torrentRecord{
+		Torrent:         torrent,
+		StateString:     torrents.LocalizedStateString(torrent, language),
+		Paused:          torrents.IsPaused(torrent.Flags),
+		AutoManaged:     torrents.IsAutoManaged(torrent.Flags),
+		ProgressPercent: torrent.Progress * 100,
+	}
+	if eta, ok := torrents.ETA(torrent); ok {
+		seconds := int64(eta.Seconds())
+		record.ETA = &seconds
+	}
+	return record
+}
+
+func formatETASeconds(eta *int64) string {
+	if eta == nil {
+		return ""
+	}
+	return strconv.FormatInt(*eta, 10)
+}
+
+func formatETA(eta *int64) string {
+	if eta == nil {
+		return "-"
+	}
+	return (time.Duration(*eta) * time.Second).String()
+}
+
+func writeTorrents(w io.Writer, format string, all []torrents.Torrent, language i18n.I18n) error {
+	if format != OutputTable {
+		language = i18n.English
+	}
+	records := make([]torrentRecord, len(all))
+	for i, torrent := range all {
+		records[i] = newTorrentRecord(torrent, language)
+	}
+
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(torrentListRecord{
+			TorrentsTotal: len(records),
+			Torrents:      records,
+		})
+	case OutputNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OutputCSV:
+		return writeColumns(csv.NewWriter(w), csvColumns, records)
+	}
+	return writeTable(w, records)
+}
+
+func writeColumns(w *csv.Writer, columns []column, records []torrentRecord) error {
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		row[i] = c.name
+	}
+	w.Write(row)
+	for _, record := range records {
+		for i, c := range columns {
+			row[i] = c.value(record)
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeTable(w io.Writer, records []torrentRecord) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, c := range tableColumns {
+		if i != 0 {
+			fmt.Fprint(tw, "\t")
+		}
+		fmt.Fprint(tw, c.name)
+	}
+	fmt.Fprintln(tw)
+	for _, record := range records {
+		for i, c := range tableColumns {
+			if i != 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, c.value(record))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"osprey/data/torrents"
+	"osprey/i18n"
+)
+
+var (
+	downloading = torrents.Torrent{
+		InfoHash:     torrents.InfoHash{"aaaa", ""},
+		Name:         "Debian, 12",
+		State:        3,
+		Progress:     0.5,
+		Size:         1_000_000,
+		DownloadRate: 1000,
+	}
+	paused = torrents.Torrent{
+		InfoHash: torrents.InfoHash{"", "bbbb"},
+		Name:     "Big Movie",
+		State:    3,
+		Flags:    1 << 4,
+		Progress: 0.25,
+		Size:     4_000_000,
+	}
+)
+
+func TestWriteTorrents(t *testing.T) {
+	// The language of the interface, machine readable formats ignore it
+	language := i18n.French
+	tests := []struct {
+		format string
+		check  func(t *testing.T, out string)
+	}{
+		{OutputCSV, func(t *testing.T, out string) {
+			rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 3 {
+				t.Fatalf("got %d rows, want a header and 2 rows", len(rows))
+			}
+			wantHeader := "info_hash_v1,info_hash_v2,name,state,state_string,error,paused,auto_managed,progress_percent,eta,size,total,total_done,download_rate,upload_rate,num_peers,num_seeds,list_peers,list_seeds,queue_position,save_path,flags"
+			if got := strings.Join(rows[0], ","); got != wantHeader {
+				t.Errorf("got header %s, want %s", got, wantHeader)
+			}
+			wantRows := [][]string{
+				{"aaaa", "", "Debian, 12", "3", "downloading", "false", "false", "false", "50.00", "500", "1000000", "0", "0", "1000", "0", "0", "0", "0", "0", "0", "", "0"},
+				{"", "bbbb", "Big Movie", "3", "paused", "false", "true", "false", "25.00", "", "4000000", "0", "0", "0", "0", "0", "0", "0", "0", "0", "", "16"},
+			}
+			for i, want := range wantRows {
+				if got := strings.Join(rows[i+1], "|"); got != strings.Join(want, "|") {
+					t.Errorf("row %d: got %s, want %s", i+1, got, strings.Join(want, "|"))
+				}
+			}
+		}},
+		{OutputNDJSON, func(t *testing.T, out string) {
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("got %d lines, want 2", len(lines))
+			}
+			records := make([]map[string]any, len(lines))
+			for i, line := range lines {
+				if err := json.Unmarshal([]byte(line), &records[i]); err != nil {
+					t.Fatalf("line %d: %v", i+1, err)
+				}
+			}
+			checkRecords(t, records)
+		}},
+		{OutputJSON, func(t *testing.T, out string) {
+			var list struct {
+				TorrentsTotal int              `json:"torrents_total"`
+				Torrents      []map[string]any `json:"torrents"`
+			}
+			if err := json.Unmarshal([]byte(out), &list); err != nil {
+				t.Fatal(err)
+			}
+			if list.TorrentsTotal != 2 {
+				t.Errorf("got torrents_total %d, want 2", list.TorrentsTotal)
+			}
+			checkRecords(t, list.Torrents)
+		}},
+		{OutputTable, func(t *testing.T, out string) {
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			if len(lines) != 3 {
+				t.Fatalf("got %d lines, want a header and 2 rows", len(lines))
+			}
+			if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "HASH STATE PROGRESS SIZE DOWN UP PEERS SEEDS ETA NAME" {
+				t.Errorf("got header %q", lines[0])
+			}
+			// The table is for humans and uses the interface language
+			for i, want := range []string{"aaaa  téléchargement  50.0%", "bbbb  interrompu      25.0%"} {
+				if !strings.HasPrefix(lines[i+1], want) {
+					t.Errorf("row %d: got %q, want it to start with %q", i+1, lines[i+1], want)
+				}
+			}
+			if !strings.Contains(lines[1], "8m20s") || !strings.Contains(lines[2], " - ") {
+				t.Errorf("unexpected ETAs in\n%s", out)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeTorrents(&out, tt.format, []torrents.Torrent{downloading, paused}, language); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, out.String())
+		})
+	}
+}
+
+// checkRecords checks the JSON objects of downloading and paused.
+func checkRecords(t *testing.T, records []map[string]any) {
+	t.Helper()
+	if len(records) != 2 {
+		t.Fatalf("got %d torrents, want 2", len(records))
+	}
+	tests := []struct {
+		key  string
+		want []any
+	}{
+		{"name", []any{"Debian, 12", "Big Movie"}},
+		{"info_hash", []any{[]any{"aaaa", nil}, []any{nil, "bbbb"}}},
+		{"state_string", []any{"downloading", "paused"}},
+		{"paused", []any{false, true}},
+		{"progress_percent", []any{50.0, 25.0}},
+		{"eta", []any{500.0, nil}},
+	}
+	for _, tt := range tests {
+		for i, record := range records {
+			got, _ := json.Marshal(record[tt.key])
+			want, _ := json.Marshal(tt.want[i])
+			if string(got) != string(want) {
+				t.Errorf("torrent %d: got %s %s, want %s", i, tt.key, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"osprey/config"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ReadPassword prompts on stderr so the output of commands can still be
+// piped, and doesn't echo what is typed. The terminal is opened directly when
+// stdin is redirected.
+func ReadPassword(prompt string) (string, error) {
+	tty := os.Stdin
+	if !term.IsTerminal(int(tty.Fd())) {
+		var err error
+		if tty, err = os.Open("/dev/tty"); err != nil {
+			return "", errors.New("a terminal is needed to read the passphrase")
+		}
+		defer tty.Close()
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(os.Stderr)
+	return string(password), err
+}
+
+// runEncryptSecret prints the secretkey_encrypted value of a token, read from
+// stdin when it isn't a terminal.
+func runEncryptSecret(ctx context.Context, args []string) error {
+	positional, err := parseInterspersed(newFlagSet("encrypt-secret"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return errUsage
+	}
+
+	var secretKey string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		if secretKey, err = ReadPassword("Secret key: "); err != nil {
+			return err
+		}
+	} else {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		secretKey = string(content)
+	}
+	secretKey = strings.TrimSpace(secretKey)
+	if secretKey == "" {
+		return errors.New("empty secret key")
+	}
+
+	passphrase, err := ReadPassword("Passphrase: ")
+	if err != nil {
+		return err
+	}
+	confirmation, err := ReadPassword("Confirm passphrase: ")
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return errors.New("empty passphrase")
+	}
+	if passphrase != confirmation {
+		return errors.New("the passphrases don't match")
+	}
+	encrypted, err := config.EncryptSecretKey(secretKey, passphrase)
+	if err != nil {
+		return err
+	}
+	fmt.Println("secretkey_encrypted: " + encrypted)
+	return nil
+}
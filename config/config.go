@@ -1,20 +1,177 @@
 package config
 
 import (
+	"fmt"
 	"osprey/i18n"
+	"time"
+)
+
+const (
+	DefaultProfileName = "default"
+	DefaultPageSize    = 10
+	// DefaultTimeoutKey sets the timeout of the calls not listed in timeouts.
+	DefaultTimeoutKey = "default"
 )
 
 var (
 	Osprey_version = "v0.0.3"
 	ConfigFilePath = "./config.yaml"
 	Config         ConfigType
+	Current        ProfileType
 )
 
+type ProfileType struct {
+	Name               string  `yaml:"name,omitempty"`
+	SecretKey          string  `yaml:"secretkey,omitempty"`
+	SecretKeyFile      string  `yaml:"secretkey_file,omitempty"`
+	SecretKeyEnv       string  `yaml:"secretkey_env,omitempty"`
+	SecretKeyCmd       string  `yaml:"secretkey_cmd,omitempty"`
+	SecretKeyEncrypted string  `yaml:"secretkey_encrypted,omitempty"`
+	JSONRPCEndpointURL string  `yaml:"JSONRPCEndpointURL,omitempty"`
+	PageSize           int     `yaml:"pagesize,omitempty"`
+	I18nLanguage       string  `yaml:"i18nlanguage,omitempty"`
+	TLS                TLSType `yaml:"tls,omitempty"`
+	HTTPProxy          string  `yaml:"http_proxy,omitempty"`
+	SOCKS5             string  `yaml:"socks5,omitempty"`
+	SSH                SSHType `yaml:"ssh,omitempty"`
+	// Timeouts of calls to Porla keyed by JSON-RPC method, such as
+	// torrents.add, or DefaultTimeoutKey.
+	Timeouts map[string]time.Duration `yaml:"timeouts,omitempty"`
+}
+
+// SSHType makes osprey reach Porla through an SSH connection to the host it
+// runs on, the endpoint is then resolved on that host.
+type SSHType struct {
+	Host         string `yaml:"host,omitempty"`
+	User         string `yaml:"user,omitempty"`
+	IdentityFile string `yaml:"identity_file,omitempty"`
+	KnownHosts   string `yaml:"known_hosts,omitempty"`
+}
+
+// TLSType configures https endpoints, for servers behind a reverse proxy with
+// a private CA or requiring client certificates.
+type TLSType struct {
+	CAFile     string `yaml:"ca_file,omitempty"`
+	CertFile   string `yaml:"cert_file,omitempty"`
+	KeyFile    string `yaml:"key_file,omitempty"`
+	ServerName string `yaml:"server_name,omitempty"`
+	// A pointer so a profile can turn it off when the top level settings
+	// turn it on
+	InsecureSkipVerify *bool `yaml:"insecure_skip_verify,omitempty"`
+}
+
+// SortFields are the orders the torrent list can be shown in.
+var SortFields = []string{"name", "size", "progress", "download_rate", "upload_rate", "peers", "seeds", "queue_position", "eta", "state"}
+
+// SortType is the order of the torrent list, Porla's own when By is empty.
+type SortType struct {
+	By         string `yaml:"by,omitempty"`
+	Descending bool   `yaml:"descending,omitempty"`
+}
+
+// The top level settings form the default profile, named profiles only need
+// to set what differs from it.
 type ConfigType struct {
-	SecretKey          string `yaml:"secretkey"`
-	JSONRPCEndpointURL string `yaml:"JSONRPCEndpointURL"`
-	PageSize           int    `yaml:"pagesize"`
-	I18nLanguage       string `yaml:"i18nlanguage"`
+	ProfileType `yaml:",inline"`
+	Profiles    []ProfileType `yaml:"profiles,omitempty"`
+	Sort        SortType      `yaml:"sort,omitempty"`
 }
 
 var Currenti18n i18n.I18n
+
+// ProfileNames lists the profiles that can be selected, the default one is
+// only included when it points to a server.
+func ProfileNames() []string {
+	var names []string
+	if Config.JSONRPCEndpointURL != "" {
+		names = append(names, DefaultProfileName)
+	}
+	for _, profile := range Config.Profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
+func Profile(name string) (ProfileType, error) {
+	profile := Config.ProfileType
+	profile.Name = DefaultProfileName
+	if name == DefaultProfileName || (name == "" && Config.JSONRPCEndpointURL != "") {
+		return profile, nil
+	}
+	for _, p := range Config.Profiles {
+		if p.Name != name && name != "" {
+			continue
+		}
+		profile = mergeProfile(profile, p)
+		profile.Name = p.Name
+		return profile, nil
+	}
+	if name == "" {
+		return profile, nil
+	}
+	return profile, fmt.Errorf("unknown profile %q", name)
+}
+
+// mergeProfile returns base with the settings set in p replacing its own.
+func mergeProfile(base, p ProfileType) ProfileType {
+	if hasSecretKeySource(p) {
+		base.SecretKey = p.SecretKey
+		base.SecretKeyFile = p.SecretKeyFile
+		base.SecretKeyEnv = p.SecretKeyEnv
+		base.SecretKeyCmd = p.SecretKeyCmd
+		base.SecretKeyEncrypted = p.SecretKeyEncrypted
+	}
+	if p.JSONRPCEndpointURL != "" {
+		base.JSONRPCEndpointURL = p.JSONRPCEndpointURL
+	}
+	if p.PageSize != 0 {
+		base.PageSize = p.PageSize
+	}
+	if p.I18nLanguage != "" {
+		base.I18nLanguage = p.I18nLanguage
+	}
+	if p.SSH.Host != "" {
+		base.SSH = p.SSH
+	}
+	if p.HTTPProxy != "" || p.SOCKS5 != "" {
+		base.HTTPProxy, base.SOCKS5 = p.HTTPProxy, p.SOCKS5
+	}
+	if p.TLS.CAFile != "" {
+		base.TLS.CAFile = p.TLS.CAFile
+	}
+	if p.TLS.CertFile != "" || p.TLS.KeyFile != "" {
+		base.TLS.CertFile, base.TLS.KeyFile = p.TLS.CertFile, p.TLS.KeyFile
+	}
+	if p.TLS.ServerName != "" {
+		base.TLS.ServerName = p.TLS.ServerName
+	}
+	if p.TLS.InsecureSkipVerify != nil {
+		base.TLS.InsecureSkipVerify = p.TLS.InsecureSkipVerify
+	}
+	if len(p.Timeouts) != 0 {
+		timeouts := make(map[string]time.Duration, len(base.Timeouts)+len(p.Timeouts))
+		for method, timeout := range base.Timeouts {
+			timeouts[method] = timeout
+		}
+		for method, timeout := range p.Timeouts {
+			timeouts[method] = timeout
+		}
+		base.Timeouts = timeouts
+	}
+	return base
+}
+
+// UseProfile makes the named profile the one osprey connects to. An empty name
+// selects the default profile, or the first named one when there is none.
+func UseProfile(name string) error {
+	profile, err := Profile(name)
+	if err != nil {
+		return err
+	}
+	Current = profile
+	if Current.PageSize <= 0 {
+		Current.PageSize = DefaultPageSize
+	}
+	Currenti18n = i18n.LoadLanguage(Current.I18nLanguage)
+	return nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeProfile(t *testing.T) {
+	yes, no := true, false
+	base := ProfileType{
+		Name:               DefaultProfileName,
+		SecretKeyFile:      "~/.porla-token",
+		JSONRPCEndpointURL: "http://localhost:1337/api/v1/jsonrpc",
+		PageSize:           20,
+		I18nLanguage:       "English",
+		HTTPProxy:          "http://proxy:3128",
+		TLS:                TLSType{CAFile: "ca.pem", CertFile: "cert.pem", KeyFile: "key.pem", InsecureSkipVerify: &yes},
+		Timeouts:           map[string]time.Duration{DefaultTimeoutKey: 5 * time.Second, "torrents.add": time.Minute},
+	}
+	tests := []struct {
+		name    string
+		profile ProfileType
+		want    func(ProfileType) ProfileType
+	}{
+		{"empty profile inherits everything", ProfileType{}, func(p ProfileType) ProfileType { return p }},
+		{"secret key source replaces the inherited one", ProfileType{SecretKeyEnv: "TOKEN"}, func(p ProfileType) ProfileType {
+			p.SecretKeyFile, p.SecretKeyEnv = "", "TOKEN"
+			return p
+		}},
+		{"scalars", ProfileType{JSONRPCEndpointURL: "https://seedbox/jsonrpc", PageSize: 50, I18nLanguage: "French"}, func(p ProfileType) ProfileType {
+			p.JSONRPCEndpointURL, p.PageSize, p.I18nLanguage = "https://seedbox/jsonrpc", 50, "French"
+			return p
+		}},
+		{"proxies replace each other", ProfileType{SOCKS5: "socks5://tor:9050"}, func(p ProfileType) ProfileType {
+			p.HTTPProxy, p.SOCKS5 = "", "socks5://tor:9050"
+			return p
+		}},
+		{"ssh", ProfileType{SSH: SSHType{Host: "seedbox", User: "porla"}}, func(p ProfileType) ProfileType {
+			p.SSH = SSHType{Host: "seedbox", User: "porla"}
+			return p
+		}},
+		{"client certificate is replaced as a pair", ProfileType{TLS: TLSType{CertFile: "other.pem", KeyFile: "other.key"}}, func(p ProfileType) ProfileType {
+			p.TLS = TLSType{CAFile: "ca.pem", CertFile: "other.pem", KeyFile: "other.key", InsecureSkipVerify: &yes}
+			return p
+		}},
+		{"insecure_skip_verify can be turned off", ProfileType{TLS: TLSType{InsecureSkipVerify: &no}}, func(p ProfileType) ProfileType {
+			p.TLS.InsecureSkipVerify = &no
+			return p
+		}},
+		{"timeouts are merged by method", ProfileType{Timeouts: map[string]time.Duration{"torrents.add": 2 * time.Minute, "torrents.list": time.Second}}, func(p ProfileType) ProfileType {
+			p.Timeouts = map[string]time.Duration{DefaultTimeoutKey: 5 * time.Second, "torrents.add": 2 * time.Minute, "torrents.list": time.Second}
+			return p
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeProfile(base, tt.profile)
+			if want := tt.want(base); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v\nwant %+v", got, want)
+			}
+		})
+	}
+	if base.Timeouts["torrents.add"] != time.Minute {
+		t.Error("mergeProfile changed the timeouts of base")
+	}
+}
+
+func TestProfile(t *testing.T) {
+	defer func(config ConfigType) { Config = config }(Config)
+	Config = ConfigType{
+		ProfileType: ProfileType{JSONRPCEndpointURL: "http://localhost/jsonrpc", PageSize: 20},
+		Profiles: []ProfileType{
+			{Name: "seedbox", JSONRPCEndpointURL: "https://seedbox/jsonrpc"},
+			{Name: "nas", PageSize: 5},
+		},
+	}
+	tests := []struct {
+		name     string
+		wantName string
+		endpoint string
+		pageSize int
+		wantErr  bool
+	}{
+		{"", DefaultProfileName, "http://localhost/jsonrpc", 20, false},
+		{DefaultProfileName, DefaultProfileName, "http://localhost/jsonrpc", 20, false},
+		{"seedbox", "seedbox", "https://seedbox/jsonrpc", 20, false},
+		{"nas", "nas", "http://localhost/jsonrpc", 5, false},
+		{"missing", "", "", 0, true},
+	}
+	for _, tt := range tests {
+		profile, err := Profile(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: got error %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if profile.Name != tt.wantName || profile.JSONRPCEndpointURL != tt.endpoint || profile.PageSize != tt.pageSize {
+			t.Errorf("%q: got %+v", tt.name, profile)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"osprey/i18n"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configFileName = "config.yaml"
+
+// Overrides replace settings of the profile osprey starts with. Environment
+// variables take precedence over the config file, and command line flags over
+// both.
+type Overrides struct {
+	JSONRPCEndpointURL string
+	SecretKey          string
+	PageSize           int
+	I18nLanguage       string
+}
+
+// ConfigFileCandidates lists where the config file is looked for, in order:
+// the --config flag, $OSPREY_CONFIG, $XDG_CONFIG_HOME/osprey (~/.config/osprey
+// when unset), the working directory and the directory of the binary.
+func ConfigFileCandidates(flagPath string) []string {
+	var candidates []string
+	if flagPath != "" {
+		candidates = append(candidates, flagPath)
+	}
+	if envPath := os.Getenv("OSPREY_CONFIG"); envPath != "" {
+		candidates = append(candidates, envPath)
+	}
+	if configDir, err := userConfigDir(); err == nil {
+		candidates = append(candidates, filepath.Join(configDir, "osprey", configFileName))
+	}
+	candidates = append(candidates, configFileName)
+	if executable, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(executable), configFileName))
+	}
+	return candidates
+}
+
+// os.UserConfigDir only follows XDG on Linux and BSDs, osprey follows it
+// everywhere.
+func userConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+// FindConfigFile returns the first candidate that exists. Paths given
+// explicitly with the flag or the environment variable must exist.
+func FindConfigFile(flagPath string) (string, error) {
+	explicit := flagPath
+	if explicit == "" {
+		explicit = os.Getenv("OSPREY_CONFIG")
+	}
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", err
+		}
+		return explicit, nil
+	}
+	candidates := ConfigFileCandidates(flagPath)
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%w, looked in %s", errNoConfigFile, strings.Join(candidates, ", "))
+}
+
+var errNoConfigFile = errors.New("no config file found")
+
+func IsNoConfigFile(err error) bool {
+	return errors.Is(err, errNoConfigFile)
+}
+
+func Load(path string) error {
+	f, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	Config = ConfigType{}
+	if err := yaml.Unmarshal(f, &Config); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	ConfigFilePath = path
+	return nil
+}
+
+// EnvOverrides reads OSPREY_ENDPOINT, OSPREY_TOKEN, OSPREY_PAGESIZE and
+// OSPREY_LANGUAGE.
+func EnvOverrides() (Overrides, error) {
+	overrides := Overrides{
+		JSONRPCEndpointURL: os.Getenv("OSPREY_ENDPOINT"),
+		SecretKey:          os.Getenv("OSPREY_TOKEN"),
+		I18nLanguage:       os.Getenv("OSPREY_LANGUAGE"),
+	}
+	if pageSize := strings.TrimSpace(os.Getenv("OSPREY_PAGESIZE")); pageSize != "" {
+		i, err := strconv.Atoi(pageSize)
+		if err != nil {
+			return overrides, fmt.Errorf("OSPREY_PAGESIZE: %q is not a number", pageSize)
+		}
+		overrides.PageSize = i
+	}
+	return overrides, nil
+}
+
+// Merge returns o with the fields set in other replacing its own.
+func (o Overrides) Merge(other Overrides) Overrides {
+	if other.JSONRPCEndpointURL != "" {
+		o.JSONRPCEndpointURL = other.JSONRPCEndpointURL
+	}
+	if other.SecretKey != "" {
+		o.SecretKey = other.SecretKey
+	}
+	if other.PageSize != 0 {
+		o.PageSize = other.PageSize
+	}
+	if other.I18nLanguage != "" {
+		o.I18nLanguage = other.I18nLanguage
+	}
+	return o
+}
+
+// ApplyOverrides changes the current profile only, switching to another
+// profile later uses its configured settings.
+func ApplyOverrides(o Overrides) {
+	Current = mergeProfile(Current, ProfileType{
+		SecretKey:          o.SecretKey,
+		JSONRPCEndpointURL: o.JSONRPCEndpointURL,
+		PageSize:           o.PageSize,
+		I18nLanguage:       o.I18nLanguage,
+	})
+	Currenti18n = i18n.LoadLanguage(Current.I18nLanguage)
+}
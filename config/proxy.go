@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ProxyURL returns the proxy the profile reaches Porla through, nil when it
+// connects directly. Both settings take host:port, or a URL to give
+// credentials.
+func ProxyURL(profile ProfileType) (*url.URL, error) {
+	switch {
+	case profile.HTTPProxy != "":
+		proxy := profile.HTTPProxy
+		if !strings.Contains(proxy, "://") {
+			proxy = "http://" + proxy
+		}
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, err
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%q is not a host:port or http(s):// address", profile.HTTPProxy)
+		}
+		return u, nil
+	case profile.SOCKS5 != "":
+		proxy := profile.SOCKS5
+		if !strings.Contains(proxy, "://") {
+			proxy = "socks5://" + proxy
+		}
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "socks5" || u.Host == "" {
+			return nil, fmt.Errorf("%q is not a host:port or socks5:// address", profile.SOCKS5)
+		}
+		return u, nil
+	}
+	return nil, nil
+}
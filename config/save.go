@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewConfigFilePath is where a config file is created when none was found:
+// the path given with --config or $OSPREY_CONFIG, the XDG location otherwise.
+func NewConfigFilePath(flagPath string) string {
+	return ConfigFileCandidates(flagPath)[0]
+}
+
+// Save writes Config to ConfigFilePath. The file is updated in place so the
+// comments and ordering of a hand written config are kept.
+func Save() error {
+	var updated yaml.Node
+	if err := updated.Encode(Config); err != nil {
+		return err
+	}
+
+	document := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&updated}}
+	if f, err := os.ReadFile(ConfigFilePath); err == nil {
+		var existing yaml.Node
+		if yaml.Unmarshal(f, &existing) == nil && len(existing.Content) == 1 && existing.Content[0].Kind == yaml.MappingNode {
+			mergeNodes(existing.Content[0], &updated)
+			document = &existing
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	out, err := yaml.Marshal(document)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ConfigFilePath), 0700); err != nil {
+		return err
+	}
+	// The file holds auth tokens, WriteFile keeps the mode of an existing file
+	if err := os.WriteFile(ConfigFilePath, out, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(ConfigFilePath, 0600)
+}
+
+// SaveSort makes sort the order of the torrent list. It is only written when
+// there is a config file, osprey run from flags alone keeps it for the session.
+func SaveSort(sort SortType) error {
+	Config.Sort = sort
+	if _, err := os.Stat(ConfigFilePath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return Save()
+}
+
+// mergeNodes makes dst hold the values of src while keeping the comments and
+// key order of dst.
+func mergeNodes(dst, src *yaml.Node) {
+	if dst.Kind != src.Kind {
+		*dst = *src
+		return
+	}
+	switch dst.Kind {
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		for i := 0; i+1 < len(dst.Content); i += 2 {
+			if value := mappingValue(src, dst.Content[i].Value); value != nil {
+				mergeNodes(dst.Content[i+1], value)
+				content = append(content, dst.Content[i], dst.Content[i+1])
+			}
+		}
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			if mappingValue(dst, src.Content[i].Value) == nil {
+				content = append(content, src.Content[i], src.Content[i+1])
+			}
+		}
+		dst.Content = content
+	case yaml.SequenceNode:
+		for i := range src.Content {
+			if i < len(dst.Content) {
+				mergeNodes(dst.Content[i], src.Content[i])
+			} else {
+				dst.Content = append(dst.Content, src.Content[i])
+			}
+		}
+		dst.Content = dst.Content[:len(src.Content)]
+	default:
+		dst.Tag, dst.Value, dst.Style = src.Tag, src.Value, src.Style
+	}
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// SetProfileConnection stores the endpoint and secret key of a profile in
+// Config, replacing where the key was read from. Call Save to write them to
+// the file.
+func SetProfileConnection(name, endpoint, secretKey string) {
+	setConnection(profileEntry(name), endpoint, secretKey)
+}
+
+// profileEntry returns the settings of the named profile as written in the
+// config file, adding the profile when it isn't there yet.
+func profileEntry(name string) *ProfileType {
+	if name == DefaultProfileName || name == "" {
+		return &Config.ProfileType
+	}
+	for i := range Config.Profiles {
+		if Config.Profiles[i].Name == name {
+			return &Config.Profiles[i]
+		}
+	}
+	Config.Profiles = append(Config.Profiles, ProfileType{Name: name})
+	return &Config.Profiles[len(Config.Profiles)-1]
+}
+
+func setConnection(profile *ProfileType, endpoint, secretKey string) {
+	profile.JSONRPCEndpointURL = endpoint
+	profile.SecretKey = secretKey
+	profile.SecretKeyFile = ""
+	profile.SecretKeyEnv = ""
+	profile.SecretKeyCmd = ""
+	profile.SecretKeyEncrypted = ""
+}
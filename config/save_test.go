@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeNodes(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  string
+		src  string
+		want string
+	}{
+		{
+			"comments and key order are kept",
+			"# Porla on this machine\nsecretkey: old # expires in March\nJSONRPCEndpointURL: http://localhost/jsonrpc\n",
+			"JSONRPCEndpointURL: http://localhost/jsonrpc\nsecretkey: new\n",
+			"# Porla on this machine\nsecretkey: new # expires in March\nJSONRPCEndpointURL: http://localhost/jsonrpc\n",
+		},
+		{
+			"removed keys are dropped and new ones appended",
+			"pagesize: 20 # more rows\ni18nlanguage: French\n",
+			"pagesize: 30\nsort:\n    by: name\n",
+			"pagesize: 30 # more rows\nsort:\n    by: name\n",
+		},
+		{
+			"sequences are merged by index",
+			"profiles:\n    # the seedbox\n    - name: seedbox\n      pagesize: 5\n    - name: nas\n",
+			"profiles:\n    - name: seedbox\n      pagesize: 10\n",
+			"profiles:\n    # the seedbox\n    - name: seedbox\n      pagesize: 10\n",
+		},
+		{
+			"nested mappings",
+			"ssh:\n    host: seedbox # over the VPN\n    user: porla\n",
+			"ssh:\n    host: seedbox.lan\n    user: porla\n",
+			"ssh:\n    host: seedbox.lan # over the VPN\n    user: porla\n",
+		},
+		{
+			"kind changes replace the node",
+			"timeouts: none\n",
+			"timeouts:\n    default: 5s\n",
+			"timeouts:\n    default: 5s\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst, src yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.dst), &dst); err != nil {
+				t.Fatal(err)
+			}
+			if err := yaml.Unmarshal([]byte(tt.src), &src); err != nil {
+				t.Fatal(err)
+			}
+			mergeNodes(dst.Content[0], src.Content[0])
+			out, err := yaml.Marshal(&dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(out); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, strings.TrimSpace(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	saltSize = 16
+	keySize  = 32
+)
+
+// ReadPassphrase asks for the passphrase of encrypted secret keys. It is set
+// by main, and left nil while the TUI owns the terminal.
+var ReadPassphrase func() (string, error)
+
+var (
+	passphrase string
+	// Secret keys read from files, commands and encrypted values, by source so
+	// switching profiles doesn't run commands again.
+	secretKeys = map[string]string{}
+)
+
+// hasSecretKeySource reports whether the profile sets where its secret key
+// comes from, it then replaces the one of the profile it is merged over.
+func hasSecretKeySource(p ProfileType) bool {
+	return p.SecretKey != "" || p.SecretKeyFile != "" || p.SecretKeyEnv != "" || p.SecretKeyCmd != "" || p.SecretKeyEncrypted != ""
+}
+
+func secretKeySources(p ProfileType) []string {
+	var sources []string
+	for _, s := range []struct{ name, value string }{
+		{"secretkey", p.SecretKey},
+		{"secretkey_file", p.SecretKeyFile},
+		{"secretkey_env", p.SecretKeyEnv},
+		{"secretkey_cmd", p.SecretKeyCmd},
+		{"secretkey_encrypted", p.SecretKeyEncrypted},
+	} {
+		if s.value != "" {
+			sources = append(sources, s.name)
+		}
+	}
+	return sources
+}
+
+// ResolveSecretKey returns profile with SecretKey read from the file,
+// environment variable, command or encrypted value the profile names.
+func ResolveSecretKey(profile ProfileType) (ProfileType, error) {
+	if profile.SecretKey != "" {
+		return profile, nil
+	}
+	var source string
+	var read func() (string, error)
+	switch {
+	case profile.SecretKeyFile != "":
+		source, read = "file:"+profile.SecretKeyFile, func() (string, error) { return readSecretKeyFile(profile.SecretKeyFile) }
+	case profile.SecretKeyEnv != "":
+		value, ok := os.LookupEnv(profile.SecretKeyEnv)
+		if !ok {
+			return profile, fmt.Errorf("profile %q: secretkey_env: $%s is not set", profile.Name, profile.SecretKeyEnv)
+		}
+		profile.SecretKey = strings.TrimSpace(value)
+		return profile, nil
+	case profile.SecretKeyCmd != "":
+		source, read = "cmd:"+profile.SecretKeyCmd, func() (string, error) { return runSecretKeyCmd(profile.SecretKeyCmd) }
+	case profile.SecretKeyEncrypted != "":
+		source, read = "encrypted:"+profile.SecretKeyEncrypted, func() (string, error) { return decryptSecretKey(profile.SecretKeyEncrypted) }
+	default:
+		return profile, nil
+	}
+
+	if secretKey, ok := secretKeys[source]; ok {
+		profile.SecretKey = secretKey
+		return profile, nil
+	}
+	secretKey, err := read()
+	if err != nil {
+		return profile, fmt.Errorf("profile %q: %w", profile.Name, err)
+	}
+	secretKeys[source] = secretKey
+	profile.SecretKey = secretKey
+	return profile, nil
+}
+
+// ExpandHome replaces a leading ~/ in paths set in the config file with the
+// home directory.
+func ExpandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[2:]), nil
+}
+
+func readSecretKeyFile(path string) (string, error) {
+	path, err := ExpandHome(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("secretkey_file: %w", err)
+	}
+	// Same rule as ssh for private keys, Windows has no such permission bits
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("secretkey_file: %s is accessible by other users, run chmod 600 on it", path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("secretkey_file: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func runSecretKeyCmd(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("secretkey_cmd: %w: %s", err, message)
+		}
+		return "", fmt.Errorf("secretkey_cmd: %w", err)
+	}
+	// Password managers like pass print the secret on the first line
+	secretKey, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(secretKey), nil
+}
+
+// UsesEncryptedSecretKey reports whether any profile needs the passphrase.
+func UsesEncryptedSecretKey() bool {
+	if Config.SecretKeyEncrypted != "" {
+		return true
+	}
+	for _, profile := range Config.Profiles {
+		if profile.SecretKeyEncrypted != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Unlock asks for the passphrase now, so it isn't needed once the TUI runs.
+func Unlock() error {
+	_, err := getPassphrase()
+	return err
+}
+
+func getPassphrase() (string, error) {
+	if passphrase != "" {
+		return passphrase, nil
+	}
+	if ReadPassphrase == nil {
+		return "", errors.New("secretkey_encrypted: no passphrase was given")
+	}
+	p, err := ReadPassphrase()
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", errors.New("secretkey_encrypted: empty passphrase")
+	}
+	passphrase = p
+	return passphrase, nil
+}
+
+func deriveKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptSecretKey returns the value of secretkey_encrypted for secretKey:
+// the scrypt salt, the AES-GCM nonce and the ciphertext, base64 encoded.
+func EncryptSecretKey(secretKey, passphrase string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	aead, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(append(salt, nonce...), nonce, []byte(secretKey), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecretKey(encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encrypted))
+	if err != nil {
+		return "", fmt.Errorf("secretkey_encrypted: %w", err)
+	}
+	p, err := getPassphrase()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < saltSize {
+		return "", errors.New("secretkey_encrypted: value is too short")
+	}
+	aead, err := deriveKey(p, data[:saltSize])
+	if err != nil {
+		return "", err
+	}
+	data = data[saltSize:]
+	if len(data) < aead.NonceSize() {
+		return "", errors.New("secretkey_encrypted: value is too short")
+	}
+	secretKey, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		passphrase = ""
+		return "", errors.New("secretkey_encrypted: wrong passphrase")
+	}
+	return string(secretKey), nil
+}
+
+// ErrSecretKeyReadOnly is returned by StoreSecretKey when the secret key comes
+// from an environment variable or a command, which osprey can't update.
+var ErrSecretKeyReadOnly = errors.New("the secret key is read from an environment variable or a command and can't be updated")
+
+// StoreSecretKey saves secretKey where the secret key of the named profile is
+// read from: the file, the encrypted value or the config file itself.
+func StoreSecretKey(name, secretKey string) error {
+	entry := profileEntry(name)
+	if !hasSecretKeySource(*entry) && hasSecretKeySource(Config.ProfileType) {
+		// Inherited from the top level settings
+		entry = &Config.ProfileType
+	}
+	switch {
+	case entry.SecretKeyEnv != "", entry.SecretKeyCmd != "":
+		return ErrSecretKeyReadOnly
+	case entry.SecretKeyFile != "":
+		path, err := ExpandHome(entry.SecretKeyFile)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(secretKey+"\n"), 0600); err != nil {
+			return err
+		}
+		secretKeys = map[string]string{}
+		return nil
+	case entry.SecretKeyEncrypted != "":
+		p, err := getPassphrase()
+		if err != nil {
+			return err
+		}
+		if entry.SecretKeyEncrypted, err = EncryptSecretKey(secretKey, p); err != nil {
+			return err
+		}
+	default:
+		entry.SecretKey = secretKey
+	}
+	secretKeys = map[string]string{}
+	return Save()
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestEncryptSecretKey(t *testing.T) {
+	defer func(p string) { passphrase = p }(passphrase)
+	tests := []struct {
+		name       string
+		secretKey  string
+		passphrase string
+		decryptAs  string
+		wantErr    bool
+	}{
+		{"round trip", "eyJhbGciOiJIUzI1NiJ9.token", "correct horse", "correct horse", false},
+		{"empty secret key", "", "correct horse", "correct horse", false},
+		{"unicode passphrase", "token", "mot de passe ébène", "mot de passe ébène", false},
+		{"wrong passphrase", "token", "correct horse", "battery staple", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := EncryptSecretKey(tt.secretKey, tt.passphrase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			passphrase = tt.decryptAs
+			got, err := decryptSecretKey(encrypted)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.secretKey {
+				t.Errorf("got %q, want %q", got, tt.secretKey)
+			}
+		})
+	}
+}
+
+func TestEncryptSecretKeyIsSalted(t *testing.T) {
+	a, err := EncryptSecretKey("token", "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := EncryptSecretKey("token", "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Error("encrypting twice gave the same value")
+	}
+}
+
+func TestDecryptSecretKeyMalformed(t *testing.T) {
+	defer func(p string) { passphrase = p }(passphrase)
+	passphrase = "passphrase"
+	for _, encrypted := range []string{
+		"not base64!",
+		base64.StdEncoding.EncodeToString([]byte("short")),
+		base64.StdEncoding.EncodeToString(make([]byte, saltSize+4)),
+	} {
+		if _, err := decryptSecretKey(encrypted); err == nil {
+			t.Errorf("%q: no error", encrypted)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"osprey/i18n"
+	"strings"
+)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// ValidateEndpoint accepts http and https URLs, and unix:///path/to/socket
+// for a Porla listening on a Unix socket.
+func ValidateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "unix" {
+		if u.Path == "" {
+			return fmt.Errorf("%q has no socket path", endpoint)
+		}
+		return nil
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q is not an http, https or unix URL", endpoint)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", endpoint)
+	}
+	return nil
+}
+
+// Settings left empty are inherited, so only the ones that are set are checked.
+func validateProfile(prefix string, profile ProfileType) []FieldError {
+	var errs []FieldError
+	if profile.JSONRPCEndpointURL != "" {
+		if err := ValidateEndpoint(profile.JSONRPCEndpointURL); err != nil {
+			errs = append(errs, FieldError{prefix + "JSONRPCEndpointURL", err.Error()})
+		}
+	}
+	if sources := secretKeySources(profile); len(sources) > 1 {
+		errs = append(errs, FieldError{prefix + sources[1], "only one of " + strings.Join(sources, ", ") + " can be set"})
+	}
+	if profile.HTTPProxy != "" && profile.SOCKS5 != "" {
+		errs = append(errs, FieldError{prefix + "socks5", "only one of http_proxy, socks5 can be set"})
+	}
+	if _, err := ProxyURL(ProfileType{HTTPProxy: profile.HTTPProxy}); err != nil {
+		errs = append(errs, FieldError{prefix + "http_proxy", err.Error()})
+	}
+	if _, err := ProxyURL(ProfileType{SOCKS5: profile.SOCKS5}); err != nil {
+		errs = append(errs, FieldError{prefix + "socks5", err.Error()})
+	}
+	if profile.SSH.Host != "" && (profile.HTTPProxy != "" || profile.SOCKS5 != "") {
+		errs = append(errs, FieldError{prefix + "ssh", "can't be used with http_proxy or socks5"})
+	}
+	if profile.SSH.Host == "" && (profile.SSH.User != "" || profile.SSH.IdentityFile != "" || profile.SSH.KnownHosts != "") {
+		errs = append(errs, FieldError{prefix + "ssh.host", "must be set"})
+	}
+	if (profile.TLS.CertFile == "") != (profile.TLS.KeyFile == "") {
+		errs = append(errs, FieldError{prefix + "tls", "cert_file and key_file must be set together"})
+	}
+	for method, timeout := range profile.Timeouts {
+		if timeout < 0 {
+			errs = append(errs, FieldError{prefix + "timeouts." + method, "must not be negative"})
+		}
+	}
+	if profile.PageSize < 0 {
+		errs = append(errs, FieldError{prefix + "pagesize", "must be positive"})
+	}
+	if profile.I18nLanguage != "" && !i18n.IsKnownLanguage(profile.I18nLanguage) {
+		errs = append(errs, FieldError{prefix + "i18nlanguage", fmt.Sprintf("unknown language %q, expected one of %s", profile.I18nLanguage, strings.Join(i18n.Languages, ", "))})
+	}
+	return errs
+}
+
+// Validate checks the loaded config file, and that the current profile has
+// an endpoint once overrides are applied.
+func Validate() error {
+	errs := validateProfile("", Config.ProfileType)
+	names := map[string]bool{}
+	for i, profile := range Config.Profiles {
+		prefix := fmt.Sprintf("profiles[%d].", i)
+		switch {
+		case profile.Name == "":
+			errs = append(errs, FieldError{prefix + "name", "must not be empty"})
+		case profile.Name == DefaultProfileName:
+			errs = append(errs, FieldError{prefix + "name", fmt.Sprintf("%q is the name of the top level profile", profile.Name)})
+		case names[profile.Name]:
+			errs = append(errs, FieldError{prefix + "name", fmt.Sprintf("%q is already used", profile.Name)})
+		}
+		names[profile.Name] = true
+		errs = append(errs, validateProfile(prefix, profile)...)
+	}
+	if Config.Sort.By != "" && !contains(SortFields, Config.Sort.By) {
+		errs = append(errs, FieldError{"sort.by", fmt.Sprintf("unknown order %q, expected one of %s", Config.Sort.By, strings.Join(SortFields, ", "))})
+	}
+	if Current.JSONRPCEndpointURL == "" {
+		errs = append(errs, FieldError{"JSONRPCEndpointURL", fmt.Sprintf("no endpoint set for profile %q", Current.Name)})
+	}
+	if len(errs) != 0 {
+		return ValidationError(errs)
+	}
+	return nil
+}
+
+// RequireSecretKey checks that the current profile has a secret key, all
+// commands but login need one.
+func RequireSecretKey() error {
+	if Current.SecretKey == "" {
+		return ValidationError{{"secretkey", fmt.Sprintf("no secret key set for profile %q, run osprey login to get one", Current.Name)}}
+	}
+	return nil
+}
+
+// NeedsSetup reports whether the current profile lacks what is needed to
+// connect, which the setup wizard asks for.
+func NeedsSetup() bool {
+	return Current.JSONRPCEndpointURL == "" || Current.SecretKey == ""
+}
+
+func (o Overrides) Validate() error {
+	errs := validateProfile("", ProfileType{
+		JSONRPCEndpointURL: o.JSONRPCEndpointURL,
+		PageSize:           o.PageSize,
+		I18nLanguage:       o.I18nLanguage,
+	})
+	for i := range errs {
+		errs[i].Field = overrideNames[errs[i].Field]
+	}
+	if len(errs) != 0 {
+		return ValidationError(errs)
+	}
+	return nil
+}
+
+var overrideNames = map[string]string{
+	"JSONRPCEndpointURL": "endpoint",
+	"pagesize":           "pagesize",
+	"i18nlanguage":       "language",
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	defer func(config ConfigType, current ProfileType) { Config, Current = config, current }(Config, Current)
+	valid := ProfileType{JSONRPCEndpointURL: "http://localhost:1337/api/v1/jsonrpc", SecretKey: "token"}
+	tests := []struct {
+		name   string
+		config ConfigType
+		// The fields with an error, in order
+		want []string
+	}{
+		{"valid", ConfigType{ProfileType: valid, Profiles: []ProfileType{{Name: "seedbox", SSH: SSHType{Host: "seedbox"}}}}, nil},
+		{"unix socket", ConfigType{ProfileType: ProfileType{JSONRPCEndpointURL: "unix:///run/porla.sock"}}, nil},
+		{"bad endpoint", ConfigType{ProfileType: ProfileType{JSONRPCEndpointURL: "ftp://localhost"}}, []string{"JSONRPCEndpointURL"}},
+		{"two secret key sources", ConfigType{ProfileType: ProfileType{JSONRPCEndpointURL: valid.JSONRPCEndpointURL, SecretKey: "a", SecretKeyEnv: "B"}}, []string{"secretkey_env"}},
+		{"two proxies", ConfigType{ProfileType: ProfileType{JSONRPCEndpointURL: valid.JSONRPCEndpointURL, HTTPProxy: "http://proxy:3128", SOCKS5: "socks5://tor:9050"}}, []string{"socks5"}},
+		{"ssh without host", ConfigType{ProfileType: valid, Profiles: []ProfileType{{Name: "seedbox", SSH: SSHType{User: "porla"}}}}, []string{"profiles[0].ssh.host"}},
+		{"lone client certificate", ConfigType{ProfileType: ProfileType{JSONRPCEndpointURL: valid.JSONRPCEndpointURL, TLS: TLSType{CertFile: "cert.pem"}}}, []string{"tls"}},
+		{"negative timeout", ConfigType{ProfileType: ProfileType{JSONRPCEndpointURL: valid.JSONRPCEndpointURL, Timeouts: map[string]time.Duration{"torrents.add": -time.Second}}}, []string{"timeouts.torrents.add"}},
+		{"negative page size", ConfigType{ProfileType: ProfileType{JSONRPCEndpointURL: valid.JSONRPCEndpointURL, PageSize: -1}}, []string{"pagesize"}},
+		{"unknown language", ConfigType{ProfileType: ProfileType{JSONRPCEndpointURL: valid.JSONRPCEndpointURL, I18nLanguage: "Klingon"}}, []string{"i18nlanguage"}},
+		{"profile names", ConfigType{ProfileType: valid, Profiles: []ProfileType{{}, {Name: DefaultProfileName}, {Name: "a"}, {Name: "a"}}}, []string{"profiles[0].name", "profiles[1].name", "profiles[3].name"}},
+		{"unknown sort", ConfigType{ProfileType: valid, Sort: SortType{By: "colour"}}, []string{"sort.by"}},
+		{"no endpoint", ConfigType{}, []string{"JSONRPCEndpointURL"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Config = tt.config
+			if err := UseProfile(""); err != nil {
+				t.Fatal(err)
+			}
+			err := Validate()
+			var got []string
+			var validationError ValidationError
+			if errors.As(err, &validationError) {
+				for _, fieldError := range validationError {
+					got = append(got, fieldError.Field)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !equal(got, tt.want) {
+				t.Errorf("got errors on %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
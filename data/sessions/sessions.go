@@ -0,0 +1,13 @@
+package sessions
+
+type Session struct {
+	Name          string `json:"name"`
+	IsDHTRunning  bool   `json:"is_dht_running"`
+	IsListening   bool   `json:"is_listening"`
+	IsPaused      bool   `json:"is_paused"`
+	TorrentsTotal int    `json:"torrents_total"`
+}
+
+type SessionList struct {
+	Sessions []Session `json:"sessions"`
+}
@@ -0,0 +1,17 @@
+package sys
+
+type LibtorrentVersion struct {
+	Version  string `json:"version"`
+	Revision string `json:"revision"`
+}
+
+type PorlaVersion struct {
+	Branch    string `json:"branch"`
+	Commitish string `json:"commitish"`
+	Version   string `json:"version"`
+}
+
+type Versions struct {
+	Libtorrent LibtorrentVersion `json:"libtorrent"`
+	Porla      PorlaVersion      `json:"porla"`
+}
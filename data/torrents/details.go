@@ -0,0 +1,113 @@
+package torrents
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// File is a file of a torrent, Progress is the number of bytes downloaded.
+type File struct {
+	Index    int    `json:"index"`
+	Path     string `json:"path"`
+	Size     uint64 `json:"size"`
+	Progress uint64 `json:"progress"`
+	Priority int    `json:"priority"`
+}
+
+type FileList struct {
+	Files []File `json:"files"`
+}
+
+// Endpoint is an address Porla sends as [ip, port].
+type Endpoint struct {
+	IP   string
+	Port int
+}
+
+func (e *Endpoint) UnmarshalJSON(data []byte) error {
+	var ipPort [2]json.RawMessage
+	if err := json.Unmarshal(data, &ipPort); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(ipPort[0], &e.IP); err != nil {
+		return err
+	}
+	return json.Unmarshal(ipPort[1], &e.Port)
+}
+
+func (e Endpoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{e.IP, e.Port})
+}
+
+// ParseEndpoint reads an ip:port address, with IPv6 addresses in brackets.
+func ParseEndpoint(address string) (Endpoint, error) {
+	host, port, err := net.SplitHostPort(strings.TrimSpace(address))
+	if err != nil {
+		return Endpoint{}, err
+	}
+	if net.ParseIP(host) == nil {
+		return Endpoint{}, fmt.Errorf("%q is not an IP address", host)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil || p <= 0 || p > 65535 {
+		return Endpoint{}, fmt.Errorf("%q is not a valid port", port)
+	}
+	return Endpoint{IP: host, Port: p}, nil
+}
+
+func (e Endpoint) String() string {
+	return net.JoinHostPort(e.IP, strconv.Itoa(e.Port))
+}
+
+// Peer is an entry of torrents.peers.list. Porla has no country for peers,
+// libtorrent stopped providing one, so the peers tab doesn't show it.
+type Peer struct {
+	Client       string   `json:"client"`
+	Endpoint     Endpoint `json:"ip"`
+	Flags        uint64   `json:"flags"`
+	Progress     float64  `json:"progress"`
+	DownloadRate uint64   `json:"down_speed"`
+	UploadRate   uint64   `json:"up_speed"`
+}
+
+type PeerList struct {
+	Peers []Peer `json:"peers"`
+}
+
+// Tracker is an announce URL of a torrent along with the result of its last
+// announce and scrape. NextAnnounce is a Unix time, 0 when none is planned.
+type Tracker struct {
+	URL          string `json:"url"`
+	Tier         int    `json:"tier"`
+	Fails        int    `json:"fails"`
+	Message      string `json:"message"`
+	Updating     bool   `json:"updating"`
+	Seeds        int    `json:"scrape_complete"`
+	Peers        int    `json:"scrape_incomplete"`
+	NextAnnounce int64  `json:"next_announce"`
+}
+
+// CheckTrackerURL tells whether url can be announced to.
+func CheckTrackerURL(s string) error {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "udp":
+	default:
+		return fmt.Errorf("%q is not an http, https or udp URL", s)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", s)
+	}
+	return nil
+}
+
+type TrackerList struct {
+	Trackers []Tracker `json:"trackers"`
+}
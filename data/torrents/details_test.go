@@ -0,0 +1,64 @@
+package torrents
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		address string
+		want    Endpoint
+		wantErr bool
+	}{
+		{"192.168.1.2:6881", Endpoint{IP: "192.168.1.2", Port: 6881}, false},
+		{" 10.0.0.1:1 ", Endpoint{IP: "10.0.0.1", Port: 1}, false},
+		{"[2001:db8::1]:51413", Endpoint{IP: "2001:db8::1", Port: 51413}, false},
+		{"2001:db8::1:51413", Endpoint{}, true},
+		{"192.168.1.2", Endpoint{}, true},
+		{"example.com:6881", Endpoint{}, true},
+		{"192.168.1.2:0", Endpoint{}, true},
+		{"192.168.1.2:65536", Endpoint{}, true},
+		{"192.168.1.2:http", Endpoint{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEndpoint(tt.address)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: got error %v, want error %v", tt.address, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %+v, want %+v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointJSON(t *testing.T) {
+	tests := []struct {
+		data     string
+		endpoint Endpoint
+		address  string
+	}{
+		{`["192.168.1.2",6881]`, Endpoint{IP: "192.168.1.2", Port: 6881}, "192.168.1.2:6881"},
+		{`["2001:db8::1",51413]`, Endpoint{IP: "2001:db8::1", Port: 51413}, "[2001:db8::1]:51413"},
+	}
+	for _, tt := range tests {
+		var endpoint Endpoint
+		if err := json.Unmarshal([]byte(tt.data), &endpoint); err != nil {
+			t.Fatalf("%s: %v", tt.data, err)
+		}
+		if endpoint != tt.endpoint {
+			t.Errorf("%s: got %+v, want %+v", tt.data, endpoint, tt.endpoint)
+		}
+		if got := endpoint.String(); got != tt.address {
+			t.Errorf("%s: got address %q, want %q", tt.data, got, tt.address)
+		}
+		data, err := json.Marshal(endpoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.data {
+			t.Errorf("got %s, want %s", data, tt.data)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package torrents
+
+import (
+	"sort"
+	"strings"
+)
+
+// File priorities of libtorrent, the ones in between are shown as the level
+// below them.
+const (
+	PrioritySkip   = 0
+	PriorityLow    = 1
+	PriorityNormal = 4
+	PriorityHigh   = 7
+)
+
+var Priorities = []int{PrioritySkip, PriorityLow, PriorityNormal, PriorityHigh}
+
+// PriorityLevel returns the index in Priorities of a file priority.
+func PriorityLevel(priority int) int {
+	level := 0
+	for i, p := range Priorities {
+		if priority >= p {
+			level = i
+		}
+	}
+	return level
+}
+
+// FileNode is a folder or a file of the tree built by FileTree. Size and
+// Progress of folders add up those of the files in them.
+type FileNode struct {
+	Name string
+	// Path from the root, which has an empty one
+	Path     string
+	File     *File
+	Children []*FileNode
+	Size     uint64
+	Progress uint64
+}
+
+// FileTree arranges files by their path, folders first and then by name.
+func FileTree(files []File) *FileNode {
+	root := &FileNode{}
+	for i := range files {
+		file := &files[i]
+		parts := strings.Split(file.Path, "/")
+		node := root
+		for depth, name := range parts[:len(parts)-1] {
+			node.Size += file.Size
+			node.Progress += file.Progress
+			folder := node.folder(name)
+			if folder == nil {
+				folder = &FileNode{Name: name, Path: strings.Join(parts[:depth+1], "/")}
+				node.Children = append(node.Children, folder)
+			}
+			node = folder
+		}
+		node.Size += file.Size
+		node.Progress += file.Progress
+		node.Children = append(node.Children, &FileNode{
+			Name:     parts[len(parts)-1],
+			Path:     file.Path,
+			File:     file,
+			Size:     file.Size,
+			Progress: file.Progress,
+		})
+	}
+	root.sort()
+	return root
+}
+
+func (n *FileNode) folder(name string) *FileNode {
+	for _, child := range n.Children {
+		if child.Name == name && child.IsFolder() {
+			return child
+		}
+	}
+	return nil
+}
+
+func (n *FileNode) sort() {
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		a, b := n.Children[i], n.Children[j]
+		if a.IsFolder() != b.IsFolder() {
+			return a.IsFolder()
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	for _, child := range n.Children {
+		child.sort()
+	}
+}
+
+func (n *FileNode) IsFolder() bool {
+	return n.File == nil
+}
+
+// Files returns the files in the node, or the node itself for a file.
+func (n *FileNode) Files() []File {
+	if !n.IsFolder() {
+		return []File{*n.File}
+	}
+	var files []File
+	for _, child := range n.Children {
+		files = append(files, child.Files()...)
+	}
+	return files
+}
+
+// PriorityLevel returns the level of the files in the node, the lowest one
+// and false when they don't all have the same.
+func (n *FileNode) PriorityLevel() (int, bool) {
+	files := n.Files()
+	if len(files) == 0 {
+		return PriorityLevel(PriorityNormal), true
+	}
+	lowest, same := PriorityLevel(files[0].Priority), true
+	for _, file := range files[1:] {
+		level := PriorityLevel(file.Priority)
+		if level != lowest {
+			same = false
+		}
+		if level < lowest {
+			lowest = level
+		}
+	}
+	return lowest, same
+}
@@ -0,0 +1,85 @@
+package torrents
+
+import "testing"
+
+func TestPriorityLevel(t *testing.T) {
+	tests := []struct {
+		priority int
+		want     int
+	}{
+		{PrioritySkip, 0},
+		{PriorityLow, 1},
+		{3, 1},
+		{PriorityNormal, 2},
+		{6, 2},
+		{PriorityHigh, 3},
+	}
+	for _, tt := range tests {
+		if got := PriorityLevel(tt.priority); got != tt.want {
+			t.Errorf("%d: got %d, want %d", tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestFileTree(t *testing.T) {
+	files := []File{
+		{Index: 0, Path: "album/b.flac", Size: 10, Progress: 10, Priority: PriorityNormal},
+		{Index: 1, Path: "album/Artwork/cover.jpg", Size: 1, Progress: 0, Priority: PrioritySkip},
+		{Index: 2, Path: "album/a.flac", Size: 20, Progress: 5, Priority: PriorityNormal},
+		{Index: 3, Path: "readme.txt", Size: 2, Progress: 2, Priority: PriorityHigh},
+	}
+	root := FileTree(files)
+	if root.Size != 33 || root.Progress != 17 {
+		t.Errorf("root: got size %d and progress %d, want 33 and 17", root.Size, root.Progress)
+	}
+	var got []string
+	var walk func(*FileNode)
+	walk = func(n *FileNode) {
+		for _, child := range n.Children {
+			got = append(got, child.Path)
+			walk(child)
+		}
+	}
+	walk(root)
+	want := []string{"album", "album/Artwork", "album/Artwork/cover.jpg", "album/a.flac", "album/b.flac", "readme.txt"}
+	if !equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	album := root.Children[0]
+	if album.Size != 31 || album.Progress != 15 || !album.IsFolder() {
+		t.Errorf("album: got %+v", album)
+	}
+	if n := len(album.Files()); n != 3 {
+		t.Errorf("album: got %d files, want 3", n)
+	}
+}
+
+func TestFileNodePriorityLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		priorities []int
+		wantLevel  int
+		wantSame   bool
+	}{
+		{"empty folder", nil, PriorityLevel(PriorityNormal), true},
+		{"same", []int{PriorityHigh, PriorityHigh}, 3, true},
+		{"same level", []int{PriorityLow, 2}, 1, true},
+		{"mixed", []int{PriorityNormal, PrioritySkip, PriorityHigh}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []File
+			for i, priority := range tt.priorities {
+				files = append(files, File{Index: i, Path: "folder/" + string(rune('a'+i)), Priority: priority})
+			}
+			node := &FileNode{}
+			if len(files) != 0 {
+				node = FileTree(files).Children[0]
+			}
+			level, same := node.PriorityLevel()
+			if level != tt.wantLevel || same != tt.wantSame {
+				t.Errorf("got %d, %v, want %d, %v", level, same, tt.wantLevel, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package torrents
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"osprey/config"
+	"osprey/i18n"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Filter selects the torrents shown in the list. The zero value, like the one
+// parsed from an empty query, matches every torrent.
+type Filter struct {
+	// The text it was parsed from
+	Text string
+
+	// Matched regardless of case, but sent to Porla as typed
+	Name           string
+	NameRegexp     *regexp.Regexp
+	States         []string
+	SavePathPrefix string
+	MinRatio       float64
+	MaxRatio       float64
+	MinSize        uint64
+	MaxSize        uint64
+	ErroredOnly    bool
+
+	// The interface language when the filter was parsed, states can be given
+	// in it too
+	language i18n.I18n
+}
+
+// ParseFilter reads a search bar query. Words are matched against the name,
+// /.../ is a regular expression on it and these terms narrow the list:
+//
+//	state:downloading,seeding   path:/data/movies   is:error
+//	ratio:>1.5   ratio:0..1   size:<700MB   size:1GB..10GB
+func ParseFilter(text string) (Filter, error) {
+	filter := Filter{Text: strings.TrimSpace(text), MaxRatio: math.Inf(1), MaxSize: math.MaxUint64, language: config.Currenti18n}
+	var words []string
+	for _, field := range strings.Fields(text) {
+		if len(field) > 2 && strings.HasPrefix(field, "/") && strings.HasSuffix(field, "/") {
+			re, err := regexp.Compile("(?i)" + field[1:len(field)-1])
+			if err != nil {
+				return filter, fmt.Errorf("%s: %w", field, err)
+			}
+			filter.NameRegexp = re
+			continue
+		}
+		key, value, found := strings.Cut(field, ":")
+		if !found {
+			words = append(words, field)
+			continue
+		}
+		var err error
+		switch key {
+		case "state":
+			for _, state := range strings.Split(value, ",") {
+				filter.States = append(filter.States, normalizeState(state))
+			}
+		case "path":
+			filter.SavePathPrefix = value
+		case "is":
+			if value != "error" {
+				return filter, fmt.Errorf("%s: only is:error is supported", field)
+			}
+			filter.ErroredOnly = true
+		case "ratio":
+			filter.MinRatio, filter.MaxRatio, err = parseRange(value, func(s string) (float64, error) {
+				return strconv.ParseFloat(s, 64)
+			}, 0, math.Inf(1))
+		case "size":
+			filter.MinSize, filter.MaxSize, err = parseRange(value, humanize.ParseBytes, 0, math.MaxUint64)
+		default:
+			words = append(words, field)
+		}
+		if err != nil {
+			return filter, fmt.Errorf("%s: %w", field, err)
+		}
+	}
+	filter.Name = strings.Join(words, " ")
+	return filter, nil
+}
+
+// parseRange reads >x, <x, x..y or x, which is an exact value.
+func parseRange[T float64 | uint64](s string, parse func(string) (T, error), min, max T) (T, T, error) {
+	value := func(s string) (T, error) {
+		v, err := parse(strings.TrimPrefix(s, "="))
+		if err != nil {
+			return v, fmt.Errorf("%q is not a valid value", s)
+		}
+		return v, nil
+	}
+	if from, to, found := strings.Cut(s, ".."); found {
+		low, err := value(from)
+		if err != nil {
+			return min, max, err
+		}
+		high, err := value(to)
+		return low, high, err
+	}
+	switch {
+	case strings.HasPrefix(s, ">"):
+		v, err := value(s[1:])
+		return v, max, err
+	case strings.HasPrefix(s, "<"):
+		v, err := value(s[1:])
+		return min, v, err
+	}
+	v, err := value(s)
+	return v, v, err
+}
+
+func normalizeState(state string) string {
+	return strings.ToLower(strings.NewReplacer("_", " ", "-", " ").Replace(state))
+}
+
+func (f Filter) IsEmpty() bool {
+	return f.Text == ""
+}
+
+// Match tells whether the torrent passes the filter.
+func (f Filter) Match(torrent Torrent) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	if f.Name != "" && !strings.Contains(strings.ToLower(torrent.Name), strings.ToLower(f.Name)) {
+		return false
+	}
+	if f.NameRegexp != nil && !f.NameRegexp.MatchString(torrent.Name) {
+		return false
+	}
+	if f.ErroredOnly && !torrent.Error {
+		return false
+	}
+	if !strings.HasPrefix(torrent.SavePath, f.SavePathPrefix) {
+		return false
+	}
+	if torrent.Ratio < f.MinRatio || torrent.Ratio > f.MaxRatio || torrent.Size < f.MinSize || torrent.Size > f.MaxSize {
+		return false
+	}
+	if len(f.States) == 0 {
+		return true
+	}
+	english := LocalizedStateString(torrent, i18n.English)
+	localized := LocalizedStateString(torrent, f.language)
+	for _, state := range f.States {
+		if state == english || state == localized {
+			return true
+		}
+	}
+	return false
+}
+
+// Query returns the filter in the Porla query language, or false when part of
+// it can only be applied by osprey.
+func (f Filter) Query() (string, bool) {
+	if f.NameRegexp != nil || len(f.States) != 0 || f.SavePathPrefix != "" || f.ErroredOnly {
+		return "", false
+	}
+	var terms []string
+	if f.Name != "" {
+		terms = append(terms, "name contains "+strconv.Quote(f.Name))
+	}
+	if f.MinRatio > 0 {
+		terms = append(terms, "ratio >= "+strconv.FormatFloat(f.MinRatio, 'f', -1, 64))
+	}
+	if !math.IsInf(f.MaxRatio, 1) {
+		terms = append(terms, "ratio <= "+strconv.FormatFloat(f.MaxRatio, 'f', -1, 64))
+	}
+	if f.MinSize > 0 {
+		terms = append(terms, "size >= "+strconv.FormatUint(f.MinSize, 10))
+	}
+	if f.MaxSize != math.MaxUint64 {
+		terms = append(terms, "size <= "+strconv.FormatUint(f.MaxSize, 10))
+	}
+	return strings.Join(terms, " and "), len(terms) != 0
+}
@@ -0,0 +1,120 @@
+package torrents
+
+import (
+	"math"
+	"testing"
+
+	"osprey/config"
+	"osprey/i18n"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		text    string
+		wantErr bool
+		check   func(Filter) bool
+	}{
+		{"", false, func(f Filter) bool { return f.IsEmpty() && f.MaxRatio == math.Inf(1) && f.MaxSize == math.MaxUint64 }},
+		{"Ubuntu  ISO", false, func(f Filter) bool { return f.Name == "Ubuntu ISO" }},
+		{"/^deb.*iso$/", false, func(f Filter) bool { return f.NameRegexp != nil && f.NameRegexp.MatchString("Debian.iso") }},
+		{"/(/", true, nil},
+		{"state:downloading,Seeding_Queued", false, func(f Filter) bool {
+			return len(f.States) == 2 && f.States[0] == "downloading" && f.States[1] == "seeding queued"
+		}},
+		{"path:/data/movies", false, func(f Filter) bool { return f.SavePathPrefix == "/data/movies" }},
+		{"is:error", false, func(f Filter) bool { return f.ErroredOnly }},
+		{"is:paused", true, nil},
+		{"ratio:>1.5", false, func(f Filter) bool { return f.MinRatio == 1.5 && math.IsInf(f.MaxRatio, 1) }},
+		{"ratio:<2", false, func(f Filter) bool { return f.MinRatio == 0 && f.MaxRatio == 2 }},
+		{"ratio:0.5..1", false, func(f Filter) bool { return f.MinRatio == 0.5 && f.MaxRatio == 1 }},
+		{"ratio:=1", false, func(f Filter) bool { return f.MinRatio == 1 && f.MaxRatio == 1 }},
+		{"ratio:high", true, nil},
+		{"size:<700MB", false, func(f Filter) bool { return f.MinSize == 0 && f.MaxSize == 700_000_000 }},
+		{"size:1GB..10GB", false, func(f Filter) bool { return f.MinSize == 1_000_000_000 && f.MaxSize == 10_000_000_000 }},
+		{"size:1GB..big", true, nil},
+		{"foo:bar", false, func(f Filter) bool { return f.Name == "foo:bar" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			filter, err := ParseFilter(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.check != nil && !tt.check(filter) {
+				t.Errorf("unexpected filter %+v", filter)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	config.Currenti18n = i18n.French
+	defer func() { config.Currenti18n = i18n.I18n{} }()
+
+	downloading := Torrent{Name: "Debian 12 ISO", SavePath: "/data/linux", State: 3, Ratio: 0.2, Size: 600_000_000}
+	seeding := Torrent{Name: "Big Movie", SavePath: "/data/movies", State: 5, Ratio: 2.5, Size: 4_000_000_000}
+	errored := Torrent{Name: "Broken", SavePath: "/data/movies", State: 3, Error: true}
+	tests := []struct {
+		text    string
+		torrent Torrent
+		want    bool
+	}{
+		{"", errored, true},
+		{"debian", downloading, true},
+		{"DEBIAN 12", downloading, true},
+		{"debian", seeding, false},
+		{"/movie$/", seeding, true},
+		{"state:downloading", downloading, true},
+		{"state:downloading", seeding, false},
+		{"state:diffusion", seeding, true},
+		{"state:downloading,seeding", seeding, true},
+		{"path:/data/movies", seeding, true},
+		{"path:/data/movies", downloading, false},
+		{"is:error", errored, true},
+		{"is:error", seeding, false},
+		{"ratio:>1", seeding, true},
+		{"ratio:>1", downloading, false},
+		{"size:<1GB", downloading, true},
+		{"size:<1GB", seeding, false},
+		{"size:1GB..10GB big", seeding, true},
+	}
+	for _, tt := range tests {
+		filter, err := ParseFilter(tt.text)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.text, err)
+		}
+		if got := filter.Match(tt.torrent); got != tt.want {
+			t.Errorf("%q on %q: got %v, want %v", tt.text, tt.torrent.Name, got, tt.want)
+		}
+	}
+}
+
+func TestFilterQuery(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{"", "", false},
+		{"ubuntu", `name contains "ubuntu"`, true},
+		{"Ubuntu", `name contains "Ubuntu"`, true},
+		{`say "hi"`, `name contains "say \"hi\""`, true},
+		{"ratio:>1.5", "ratio >= 1.5", true},
+		{"ratio:0..2 size:<1kB", "ratio <= 2 and size <= 1000", true},
+		{"debian size:1MB..2MB", `name contains "debian" and size >= 1000000 and size <= 2000000`, true},
+		{"/deb/", "", false},
+		{"state:seeding", "", false},
+		{"path:/data", "", false},
+		{"is:error ubuntu", "", false},
+	}
+	for _, tt := range tests {
+		filter, err := ParseFilter(tt.text)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.text, err)
+		}
+		got, ok := filter.Query()
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%q: got %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package torrents
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"strings"
+
+	"osprey/config"
+)
+
+// Bits of the peer flags of libtorrent
+const (
+	peerOutgoingConnection = 5
+	peerSeed               = 10
+	peerSnubbed            = 12
+	peerUTPSocket          = 17
+	peerRC4Encrypted       = 19
+	peerPlaintextEncrypted = 20
+)
+
+// PeerFlags sums up the connection to a peer: I for incoming, E for
+// encrypted, P for µTP, S for a seed and X when it is snubbed.
+func PeerFlags(peer Peer) string {
+	s := ""
+	if !checkBit(peer.Flags, peerOutgoingConnection) {
+		s += "I"
+	}
+	if checkBit(peer.Flags, peerRC4Encrypted) || checkBit(peer.Flags, peerPlaintextEncrypted) {
+		s += "E"
+	}
+	if checkBit(peer.Flags, peerUTPSocket) {
+		s += "P"
+	}
+	if checkBit(peer.Flags, peerSeed) {
+		s += "S"
+	}
+	if checkBit(peer.Flags, peerSnubbed) {
+		s += "X"
+	}
+	return s
+}
+
+// PeerSortFields are the orders the peer table can be shown in.
+var PeerSortFields = []string{"address", "client", "progress", "download_rate", "upload_rate", "flags"}
+
+var peerLess = map[string]func(a, b Peer) bool{
+	"address": func(a, b Peer) bool {
+		ipA, ipB := net.ParseIP(a.Endpoint.IP), net.ParseIP(b.Endpoint.IP)
+		if c := bytes.Compare(ipA.To16(), ipB.To16()); c != 0 {
+			return c < 0
+		}
+		return a.Endpoint.Port < b.Endpoint.Port
+	},
+	"client": func(a, b Peer) bool {
+		return strings.ToLower(a.Client) < strings.ToLower(b.Client)
+	},
+	"progress": func(a, b Peer) bool {
+		return a.Progress < b.Progress
+	},
+	"download_rate": func(a, b Peer) bool {
+		return a.DownloadRate < b.DownloadRate
+	},
+	"upload_rate": func(a, b Peer) bool {
+		return a.UploadRate < b.UploadRate
+	},
+	"flags": func(a, b Peer) bool {
+		return PeerFlags(a) < PeerFlags(b)
+	},
+}
+
+// SortPeers orders the peers like Sort does torrents.
+func SortPeers(peers []Peer, order config.SortType) {
+	compare, ok := peerLess[order.By]
+	if !ok {
+		return
+	}
+	sort.SliceStable(peers, func(i, j int) bool {
+		if order.Descending {
+			return compare(peers[j], peers[i])
+		}
+		return compare(peers[i], peers[j])
+	})
+}
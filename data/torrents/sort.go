@@ -0,0 +1,81 @@
+package torrents
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"osprey/config"
+)
+
+// less compares torrents on each of config.SortFields.
+var less = map[string]func(a, b Torrent) bool{
+	"name": func(a, b Torrent) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	},
+	"size": func(a, b Torrent) bool {
+		return a.Size < b.Size
+	},
+	"progress": func(a, b Torrent) bool {
+		return a.Progress < b.Progress
+	},
+	"download_rate": func(a, b Torrent) bool {
+		return a.DownloadRate < b.DownloadRate
+	},
+	"upload_rate": func(a, b Torrent) bool {
+		return a.UploadRate < b.UploadRate
+	},
+	"peers": func(a, b Torrent) bool {
+		return a.NumPeers < b.NumPeers
+	},
+	"seeds": func(a, b Torrent) bool {
+		return a.NumSeeds < b.NumSeeds
+	},
+	"queue_position": func(a, b Torrent) bool {
+		return a.QueuePosition < b.QueuePosition
+	},
+	"eta": func(a, b Torrent) bool {
+		return etaOrInfinity(a) < etaOrInfinity(b)
+	},
+	"state": func(a, b Torrent) bool {
+		return a.State < b.State
+	},
+}
+
+// Torrents that aren't downloading come after the ones that are.
+func etaOrInfinity(torrent Torrent) time.Duration {
+	if eta, ok := ETA(torrent); ok {
+		return eta
+	}
+	return math.MaxInt64
+}
+
+func lessFunc(order config.SortType) func(a, b Torrent) bool {
+	compare, ok := less[order.By]
+	if !ok {
+		return nil
+	}
+	if order.Descending {
+		return func(a, b Torrent) bool {
+			return compare(b, a)
+		}
+	}
+	return compare
+}
+
+// Sort orders the torrents, keeping Porla's order between equal ones.
+func Sort(torrentList []Torrent, order config.SortType) {
+	if compare := lessFunc(order); compare != nil {
+		sort.SliceStable(torrentList, func(i, j int) bool {
+			return compare(torrentList[i], torrentList[j])
+		})
+	}
+}
+
+func IsSorted(torrentList []Torrent, order config.SortType) bool {
+	compare := lessFunc(order)
+	return compare == nil || sort.SliceIsSorted(torrentList, func(i, j int) bool {
+		return compare(torrentList[i], torrentList[j])
+	})
+}
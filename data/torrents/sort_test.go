@@ -0,0 +1,79 @@
+package torrents
+
+import (
+	"testing"
+
+	"osprey/config"
+)
+
+func names(torrentList []Torrent) []string {
+	var names []string
+	for _, torrent := range torrentList {
+		names = append(names, torrent.Name)
+	}
+	return names
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSort(t *testing.T) {
+	list := []Torrent{
+		{Name: "b", Size: 2_000_000, State: 3, Progress: 0.5, DownloadRate: 100},
+		{Name: "C", Size: 1, State: 5},
+		{Name: "a", Size: 2_000_000, State: 3, Progress: 0.5, DownloadRate: 1000},
+		{Name: "d", Size: 3, State: 3},
+	}
+	tests := []struct {
+		order config.SortType
+		want  []string
+	}{
+		{config.SortType{By: "name"}, []string{"a", "b", "C", "d"}},
+		{config.SortType{By: "name", Descending: true}, []string{"d", "C", "b", "a"}},
+		// Equal sizes keep Porla's order
+		{config.SortType{By: "size"}, []string{"C", "d", "b", "a"}},
+		{config.SortType{By: "size", Descending: true}, []string{"b", "a", "d", "C"}},
+		// Torrents without an ETA come last
+		{config.SortType{By: "eta"}, []string{"a", "b", "C", "d"}},
+		{config.SortType{}, []string{"b", "C", "a", "d"}},
+		{config.SortType{By: "unknown"}, []string{"b", "C", "a", "d"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]Torrent(nil), list...)
+		Sort(sorted, tt.order)
+		if got := names(sorted); !equal(got, tt.want) {
+			t.Errorf("%+v: got %v, want %v", tt.order, got, tt.want)
+		}
+		if !IsSorted(sorted, tt.order) {
+			t.Errorf("%+v: IsSorted is false after Sort", tt.order)
+		}
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	list := []Torrent{{Name: "a", Size: 3}, {Name: "b", Size: 1}, {Name: "c", Size: 1}}
+	tests := []struct {
+		order config.SortType
+		want  bool
+	}{
+		{config.SortType{By: "name"}, true},
+		{config.SortType{By: "name", Descending: true}, false},
+		{config.SortType{By: "size"}, false},
+		{config.SortType{By: "size", Descending: true}, true},
+		{config.SortType{}, true},
+	}
+	for _, tt := range tests {
+		if got := IsSorted(list, tt.order); got != tt.want {
+			t.Errorf("%+v: got %v, want %v", tt.order, got, tt.want)
+		}
+	}
+}
@@ -1,30 +1,54 @@
 package torrents
 
-import "osprey/config"
+import (
+	"encoding/json"
+	"osprey/config"
+	"osprey/i18n"
+	"time"
+)
+
+// InfoHash holds the v1 and v2 info hashes of a torrent. Porla expects a
+// missing hash to be sent as null rather than an empty string.
+type InfoHash [2]string
+
+// String returns the v1 info hash, or the v2 one for v2 only torrents.
+func (h InfoHash) String() string {
+	if h[0] != "" {
+		return h[0]
+	}
+	return h[1]
+}
 
-type RequestError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+func (h InfoHash) MarshalJSON() ([]byte, error) {
+	hashes := [2]*string{}
+	for i := range h {
+		if h[i] != "" {
+			hashes[i] = &h[i]
+		}
+	}
+	return json.Marshal(hashes)
 }
 
 type Torrent struct {
-	DownloadRate  uint64    `json:"download_rate"`
-	UploadRate    uint64    `json:"upload_rate"`
-	Error         bool      `json:"error"`
-	Flags         uint64    `json:"flags"`
-	InfoHash      [2]string `json:"info_hash"`
-	ListPeers     uint64    `json:"list_peers"`
-	ListSeeds     uint64    `json:"list_seeds"`
-	Name          string    `json:"name"`
-	NumPeers      uint64    `json:"num_peers"`
-	NumSeeds      uint64    `json:"num_seeds"`
-	Progress      float64   `json:"progress"`
-	QueuePosition int64     `json:"queue_position"`
-	SavePath      string    `json:"save_path"`
-	Size          uint64    `json:"size"`
-	State         uint      `json:"state"`
-	Total         uint64    `json:"total"`
-	TotalDone     uint64    `json:"total_done"`
+	AddedOn       int64    `json:"added_on"`
+	DownloadRate  uint64   `json:"download_rate"`
+	UploadRate    uint64   `json:"upload_rate"`
+	Error         bool     `json:"error"`
+	Flags         uint64   `json:"flags"`
+	InfoHash      InfoHash `json:"info_hash"`
+	ListPeers     uint64   `json:"list_peers"`
+	ListSeeds     uint64   `json:"list_seeds"`
+	Name          string   `json:"name"`
+	NumPeers      uint64   `json:"num_peers"`
+	NumSeeds      uint64   `json:"num_seeds"`
+	Progress      float64  `json:"progress"`
+	QueuePosition int64    `json:"queue_position"`
+	Ratio         float64  `json:"ratio"`
+	SavePath      string   `json:"save_path"`
+	Size          uint64   `json:"size"`
+	State         uint     `json:"state"`
+	Total         uint64   `json:"total"`
+	TotalDone     uint64   `json:"total_done"`
 }
 
 type TorrentList struct {
@@ -34,12 +58,6 @@ type TorrentList struct {
 	TorrentsTotal int       `json:"torrents_total"`
 }
 
-type TorrentListRequestResponse struct {
-	JSONRPC string       `json:"jsonrpc"`
-	Result  TorrentList  `json:"result"`
-	Error   RequestError `json:"error"`
-}
-
 type TorrentProperties struct {
 	DownloadLimit  int    `json:"download_limit"`
 	UploadLimit    int    `json:"upload_limit"`
@@ -48,12 +66,6 @@ type TorrentProperties struct {
 	MaxUploads     int    `json:"max_uploads"`
 }
 
-type TorrentPropertiesRequestResponse struct {
-	JSONRPC string            `json:"jsonrpc"`
-	Result  TorrentProperties `json:"result"`
-	Error   RequestError      `json:"error"`
-}
-
 type TorrentPropertiesSetData struct {
 	IsAutomaticallyManaged    bool
 	IsSequenciallyDownloading bool
@@ -79,6 +91,15 @@ func IsPaused(flags uint64) bool {
 	return (flags & (1 << 4)) == 1<<4
 }
 
+// ETA estimates the time left for a downloading torrent at its current rate.
+// It returns false when the torrent isn't downloading or the rate is zero.
+func ETA(torrent Torrent) (time.Duration, bool) {
+	if torrent.State != 3 || torrent.DownloadRate == 0 {
+		return 0, false
+	}
+	return time.Duration(((1.0 - torrent.Progress) * float64(torrent.Size) * 1000000000.0 / float64(torrent.DownloadRate))).Round(time.Second), true
+}
+
 func StateColor(torrent Torrent) string {
 	if torrent.Error {
 		return "1"
@@ -94,42 +115,48 @@ func StateColor(torrent Torrent) string {
 }
 
 func StateString(torrent Torrent) string {
+	return LocalizedStateString(torrent, config.Currenti18n)
+}
+
+// LocalizedStateString is StateString in the given language, used where the
+// output has to stay the same regardless of the configured language.
+func LocalizedStateString(torrent Torrent, language i18n.I18n) string {
 	if torrent.Error {
-		return config.Currenti18n.TorrentStates.Error
+		return language.TorrentStates.Error
 	}
 
 	switch torrent.State {
 	case 1:
 		{
 			if IsPaused(torrent.Flags) {
-				return config.Currenti18n.TorrentStates.FileCheckQueued
+				return language.TorrentStates.FileCheckQueued
 			}
-			return config.Currenti18n.TorrentStates.CheckingFiles
+			return language.TorrentStates.CheckingFiles
 		}
 	case 2:
-		return config.Currenti18n.TorrentStates.DownloadingMetadata
+		return language.TorrentStates.DownloadingMetadata
 	case 3:
 		{
 			if IsPaused(torrent.Flags) {
 				if IsAutoManaged(torrent.Flags) {
-					return config.Currenti18n.TorrentStates.Queued
+					return language.TorrentStates.Queued
 				}
-				return config.Currenti18n.TorrentStates.Paused
+				return language.TorrentStates.Paused
 			}
-			return config.Currenti18n.TorrentStates.Downloading
+			return language.TorrentStates.Downloading
 		}
 	case 4:
-		return config.Currenti18n.TorrentStates.Finished
+		return language.TorrentStates.Finished
 	case 5:
 		{
 			if IsPaused(torrent.Flags) {
 				if IsAutoManaged(torrent.Flags) {
-					return config.Currenti18n.TorrentStates.SeedingQueued
+					return language.TorrentStates.SeedingQueued
 				}
-				return config.Currenti18n.TorrentStates.Finished
+				return language.TorrentStates.Finished
 			}
-			return config.Currenti18n.TorrentStates.Seeding
+			return language.TorrentStates.Seeding
 		}
 	}
-	return config.Currenti18n.TorrentStates.Unknown
+	return language.TorrentStates.Unknown
 }
@@ -0,0 +1,66 @@
+package torrents
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInfoHashMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		hash InfoHash
+		want string
+	}{
+		{"v1 only", InfoHash{"aaaa", ""}, `["aaaa",null]`},
+		{"v2 only", InfoHash{"", "bbbb"}, `[null,"bbbb"]`},
+		{"hybrid", InfoHash{"aaaa", "bbbb"}, `["aaaa","bbbb"]`},
+		{"empty", InfoHash{}, `[null,null]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInfoHashUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want InfoHash
+	}{
+		{`["aaaa",null]`, InfoHash{"aaaa", ""}},
+		{`[null,"bbbb"]`, InfoHash{"", "bbbb"}},
+		{`["aaaa","bbbb"]`, InfoHash{"aaaa", "bbbb"}},
+	}
+	for _, tt := range tests {
+		var got InfoHash
+		if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+			t.Fatalf("%s: %v", tt.data, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestInfoHashString(t *testing.T) {
+	tests := []struct {
+		hash InfoHash
+		want string
+	}{
+		{InfoHash{"aaaa", "bbbb"}, "aaaa"},
+		{InfoHash{"", "bbbb"}, "bbbb"},
+		{InfoHash{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.hash.String(); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.hash, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"osprey/jsonrpc"
+)
+
+// loginMethod names the login request in the timeouts of a profile.
+const loginMethod = "auth.login"
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	Token string `json:"token"`
+}
+
+// loginURL returns the auth endpoint of the Porla instance, which sits next to
+// the JSON-RPC one: /api/v1/jsonrpc becomes /api/v1/auth/login.
+func loginURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	return u.ResolveReference(&url.URL{Path: "auth/login"}).String(), nil
+}
+
+// Login exchanges a username and password for a token. It is never retried,
+// every attempt creates a token.
+func (c *Connection) Login(ctx context.Context, username, password string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	endpoint, err := loginURL(c.client.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(loginRequest{Username: username, Password: password})
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := withTimeout(ctx, c.Timeout(loginMethod))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", &jsonrpc.StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	var response loginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+	if response.Token == "" {
+		return "", errors.New("porla returned no token")
+	}
+	return response.Token, nil
+}
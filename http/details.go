@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+
+	"osprey/data/torrents"
+	"osprey/jsonrpc"
+)
+
+// DetailsParts selects what GetTorrentDetails loads.
+type DetailsParts struct {
+	// The torrent list snapshot already brings the properties of the
+	// torrent shown by the detail view of the current server
+	Properties bool
+	Files      bool
+	Peers      bool
+	Trackers   bool
+}
+
+// Details is what the detail view of a torrent refreshes on every tick, parts
+// that weren't asked for are left empty.
+type Details struct {
+	Properties *torrents.TorrentProperties
+	Files      []torrents.File
+	Peers      []torrents.Peer
+	Trackers   []torrents.Tracker
+}
+
+// GetTorrentDetails loads the parts of a torrent selected by parts in a
+// single round trip.
+func (c *Connection) GetTorrentDetails(ctx context.Context, torrent torrents.Torrent, parts DetailsParts) (Details, error) {
+	var details Details
+	var properties torrents.TorrentProperties
+	var fileList torrents.FileList
+	var peerList torrents.PeerList
+	var trackerList torrents.TrackerList
+	params := infoHashParams{InfoHash: torrent.InfoHash}
+	var calls []*jsonrpc.BatchCall
+	if parts.Properties {
+		calls = append(calls, &jsonrpc.BatchCall{Method: "torrents.properties.get", Params: params, Result: &properties})
+	}
+	if parts.Files {
+		calls = append(calls, &jsonrpc.BatchCall{Method: "torrents.files.list", Params: params, Result: &fileList})
+	}
+	if parts.Peers {
+		calls = append(calls, &jsonrpc.BatchCall{Method: "torrents.peers.list", Params: params, Result: &peerList})
+	}
+	if parts.Trackers {
+		calls = append(calls, &jsonrpc.BatchCall{Method: "torrents.trackers.list", Params: params, Result: &trackerList})
+	}
+	if err := c.batch(ctx, calls); err != nil {
+		return details, err
+	}
+	for _, call := range calls {
+		if call.Err != nil {
+			return details, call.Err
+		}
+	}
+	if parts.Properties {
+		details.Properties = &properties
+	}
+	details.Files, details.Peers, details.Trackers = fileList.Files, peerList.Peers, trackerList.Trackers
+	return details, nil
+}
+
+type filePriority struct {
+	Index    int `json:"index"`
+	Priority int `json:"priority"`
+}
+
+type filePrioritiesSetParams struct {
+	InfoHash   torrents.InfoHash `json:"info_hash"`
+	Priorities []filePriority    `json:"priorities"`
+}
+
+// SetFilePriorities sets the priority of every given file in a single call,
+// files left out keep theirs.
+func (c *Connection) SetFilePriorities(ctx context.Context, torrent torrents.Torrent, files []torrents.File, priority int) error {
+	params := filePrioritiesSetParams{InfoHash: torrent.InfoHash}
+	for _, file := range files {
+		params.Priorities = append(params.Priorities, filePriority{Index: file.Index, Priority: priority})
+	}
+	return c.call(ctx, "torrents.files.set", params, nil)
+}
+
+type peersAddParams struct {
+	InfoHash torrents.InfoHash   `json:"info_hash"`
+	Peers    []torrents.Endpoint `json:"peers"`
+}
+
+// AddPeer makes Porla connect the torrent to a peer.
+func (c *Connection) AddPeer(ctx context.Context, torrent torrents.Torrent, peer torrents.Endpoint) error {
+	return c.call(ctx, "torrents.peers.add", peersAddParams{
+		InfoHash: torrent.InfoHash,
+		Peers:    []torrents.Endpoint{peer},
+	}, nil)
+}
@@ -1,184 +1,651 @@
 package http
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"encoding/base64"
-	"encoding/json"
-	"io/ioutil"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
 	"net/http"
 	"os"
 	"osprey/config"
+	"osprey/data/sessions"
+	"osprey/data/sys"
 	"osprey/data/torrents"
-	"osprey/utils"
+	"osprey/jsonrpc"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-var client *http.Client
+// Connection talks to the Porla instance of a single profile.
+type Connection struct {
+	Profile config.ProfileType
+	client  *jsonrpc.Client
+	// Set when the profile's transport settings are unusable, every call
+	// returns it.
+	err error
+	// Set once Porla rejected a batch, calls are then sent one by one
+	noBatch atomic.Bool
+	// Set once Porla failed to apply a filter query, filters are then
+	// applied by osprey
+	noQuery atomic.Bool
+	// Set once Porla rejected order_by, the list is then sorted by osprey
+	noOrderBy atomic.Bool
+
+	// The whole torrent list osprey filters or sorts itself, see
+	// allTorrents. Calls that change something drop it.
+	allMu         sync.Mutex
+	all           []torrents.Torrent
+	allLoaded     time.Time
+	allGeneration int
+}
+
+// ListOptions are the filter and order of the torrent list.
+type ListOptions struct {
+	Filter torrents.Filter
+	Sort   config.SortType
+}
+
+// Default is the connection to the current profile.
+var Default *Connection
+
+type torrentListParams struct {
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+	Filters    *torrentListFilters `json:"filters,omitempty"`
+	OrderBy    string              `json:"order_by,omitempty"`
+	OrderByDir string              `json:"order_by_dir,omitempty"`
+}
+
+// orderBy maps config.SortFields to the names Porla sorts on, osprey sorts on
+// the others.
+var orderBy = map[string]string{
+	"name":           "name",
+	"size":           "size",
+	"progress":       "progress",
+	"download_rate":  "download_rate",
+	"upload_rate":    "upload_rate",
+	"peers":          "num_peers",
+	"seeds":          "num_seeds",
+	"queue_position": "queue_position",
+	"state":          "state",
+}
+
+type torrentListFilters struct {
+	Query string `json:"query"`
+}
+
+type torrentAddMetadata struct {
+	Source string `json:"source"`
+}
+
+type torrentAddParams struct {
+	MagnetURI   string             `json:"magnet_uri,omitempty"`
+	TorrentInfo string             `json:"ti,omitempty"`
+	SavePath    string             `json:"save_path"`
+	Metadata    torrentAddMetadata `json:"metadata"`
+}
+
+type torrentRemoveParams struct {
+	InfoHashes []torrents.InfoHash `json:"info_hashes"`
+	RemoveData bool                `json:"remove_data"`
+}
+
+type infoHashParams struct {
+	InfoHash torrents.InfoHash `json:"info_hash"`
+}
+
+type torrentMoveParams struct {
+	InfoHash torrents.InfoHash `json:"info_hash"`
+	Path     string            `json:"path"`
+}
+
+type torrentPropertiesSetParams struct {
+	InfoHash           torrents.InfoHash `json:"info_hash"`
+	AutoManaged        bool              `json:"auto_managed"`
+	DownloadLimit      *int              `json:"download_limit,omitempty"`
+	MaxConnections     *int              `json:"max_connections,omitempty"`
+	MaxUploads         *int              `json:"max_uploads,omitempty"`
+	SequentialDownload bool              `json:"sequential_download"`
+	SetFlags           int               `json:"set_flags"`
+	UnsetFlags         int               `json:"unset_flags"`
+	UploadLimit        *int              `json:"upload_limit,omitempty"`
+}
 
 func InitHTTPClient() {
-	client = &http.Client{
-		CheckRedirect: redirectPolicyFunc,
+	if Default != nil {
+		Default.Close()
+	}
+	Default = Connect(config.Current)
+}
+
+func Connect(profile config.ProfileType) *Connection {
+	httpClient, endpoint, err := newHTTPClient(profile)
+	return &Connection{
+		Profile: profile,
+		client:  jsonrpc.NewClient(endpoint, profile.SecretKey, httpClient),
+		err:     err,
+	}
+}
+
+// Close closes the idle connections to Porla. The SSH tunnel, if any, is
+// shared with the other profiles on the same host and stays open.
+func (c *Connection) Close() {
+	if c.err == nil {
+		c.client.HTTPClient.CloseIdleConnections()
+	}
+}
+
+const (
+	// DefaultTimeout applies to calls without a timeout in the profile.
+	DefaultTimeout = 10 * time.Second
+	maxAttempts    = 3
+	retryDelay     = 200 * time.Millisecond
+)
+
+// Only calls that don't change anything are retried, a mutating call that
+// failed midway may still have been applied by Porla.
+var idempotentMethods = map[string]bool{
+	"sessions.list":           true,
+	"sys.versions":            true,
+	"torrents.files.list":     true,
+	"torrents.list":           true,
+	"torrents.peers.list":     true,
+	"torrents.properties.get": true,
+	"torrents.trackers.list":  true,
+}
+
+// call makes a single attempt for mutating methods and up to maxAttempts for
+// idempotent ones, each attempt bounded by the method's timeout. Cancelling
+// ctx stops the call and any retry.
+func (c *Connection) call(ctx context.Context, method string, params, result any) error {
+	return c.attempt(ctx, []string{method}, func(ctx context.Context) error {
+		return c.client.Call(ctx, method, params, result)
+	})
+}
+
+// batch sends calls in one request, which is retried like a single call when
+// all of them are idempotent. The error of each call is in its Err. Once the
+// server has refused a batch, the calls of later ones are sent one by one
+// right away.
+func (c *Connection) batch(ctx context.Context, calls []*jsonrpc.BatchCall) error {
+	if !c.noBatch.Load() {
+		methods := make([]string, len(calls))
+		for i, call := range calls {
+			methods[i] = call.Method
+		}
+		err := c.attempt(ctx, methods, func(ctx context.Context) error {
+			return c.client.Batch(ctx, calls)
+		})
+		if !errors.Is(err, jsonrpc.ErrBatchUnsupported) {
+			return err
+		}
+		c.noBatch.Store(true)
+	}
+	for _, call := range calls {
+		call.Err = c.call(ctx, call.Method, call.Params, call.Result)
+		if ctx.Err() != nil || !isCallError(call.Err) {
+			return call.Err
+		}
 	}
+	return nil
+}
+
+// isCallError reports whether err only concerns the call that returned it,
+// rather than the connection to Porla.
+func isCallError(err error) bool {
+	var requestError *jsonrpc.RequestError
+	return err == nil || errors.As(err, &requestError)
+}
+
+func (c *Connection) attempt(ctx context.Context, methods []string, send func(context.Context) error) error {
+	if c.err != nil {
+		return c.err
+	}
+	attempts := maxAttempts
+	var timeout time.Duration
+	for _, method := range methods {
+		if !idempotentMethods[method] {
+			attempts = 1
+		}
+		// A batch gets the longest timeout of its calls
+		if t := c.Timeout(method); t <= 0 || timeout < 0 {
+			timeout = -1
+		} else if t > timeout {
+			timeout = t
+		}
+	}
+	if attempts == 1 {
+		defer c.dropAllTorrents()
+	}
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, backoff(attempt)); err != nil {
+				return err
+			}
+		}
+		attemptCtx, cancel := withTimeout(ctx, timeout)
+		err = send(attemptCtx)
+		cancel()
+		if err == nil || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// Timeout returns the timeout of method in the profile, or its default one.
+// Zero means no timeout.
+func (c *Connection) Timeout(method string) time.Duration {
+	if timeout, ok := c.Profile.Timeouts[method]; ok {
+		return timeout
+	}
+	if timeout, ok := c.Profile.Timeouts[config.DefaultTimeoutKey]; ok {
+		return timeout
+	}
+	return DefaultTimeout
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// backoff doubles the delay on each attempt, picking it at random in its
+// upper half so clients that failed together don't retry together.
+func backoff(attempt int) time.Duration {
+	delay := retryDelay << (attempt - 1)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Transport failures, timeouts and overloaded servers are worth another try,
+// errors returned by Porla itself would only come back.
+func isRetryable(err error) bool {
+	var statusError *jsonrpc.StatusError
+	if errors.As(err, &statusError) {
+		return statusError.StatusCode == http.StatusTooManyRequests || statusError.StatusCode >= 500
+	}
+	var netError net.Error
+	return errors.As(err, &netError) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF)
 }
 
 func redirectPolicyFunc(req *http.Request, via []*http.Request) error {
 	return nil
 }
 
-func UpdateTorrentList(page int) (torrents.TorrentList, int) {
-	requestBody := []byte(`{
-		"jsonrpc": "2.0",
-		"method": "torrents.list",
-		"params": {
-			"page": ` + strconv.Itoa(page) + `,
-  			"page_size": ` + strconv.Itoa(config.Config.PageSize) + `
+// Porla rejects requests with a missing or invalid token before they reach
+// the JSON-RPC handler.
+func IsUnauthorized(err error) bool {
+	var statusError *jsonrpc.StatusError
+	return errors.As(err, &statusError) && statusError.StatusCode == http.StatusUnauthorized
+}
+
+func (c *Connection) GetVersions(ctx context.Context) (sys.Versions, error) {
+	var versions sys.Versions
+	err := c.call(ctx, "sys.versions", nil, &versions)
+	return versions, err
+}
+
+func (c *Connection) GetTorrentList(ctx context.Context, page, pageSize int) (torrents.TorrentList, error) {
+	return c.listTorrents(ctx, torrentListParams{Page: page, PageSize: pageSize})
+}
+
+func (c *Connection) listTorrents(ctx context.Context, params torrentListParams) (torrents.TorrentList, error) {
+	var torrentList torrents.TorrentList
+	err := c.call(ctx, "torrents.list", params, &torrentList)
+	return torrentList, err
+}
+
+const allTorrentsPageSize = 100
+
+func (c *Connection) GetAllTorrents(ctx context.Context) ([]torrents.Torrent, error) {
+	var all []torrents.Torrent
+	for page := 0; ; page++ {
+		torrentList, err := c.GetTorrentList(ctx, page, allTorrentsPageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, torrentList.Torrents...)
+		if len(torrentList.Torrents) == 0 || len(all) >= torrentList.TorrentsTotal {
+			return all, nil
+		}
+	}
+}
+
+// UpdateTorrentList loads a page of the torrent list. A page past the end,
+// left behind when torrents were removed, is replaced by the last one.
+func (c *Connection) UpdateTorrentList(ctx context.Context, page, pageSize int) (torrents.TorrentList, int, error) {
+	return c.updateTorrentList(ctx, torrentListParams{Page: page, PageSize: pageSize})
+}
+
+func (c *Connection) updateTorrentList(ctx context.Context, params torrentListParams) (torrents.TorrentList, int, error) {
+	page := params.Page
+	torrentList, err := c.listTorrents(ctx, params)
+	var requestError *jsonrpc.RequestError
+	pastEnd := errors.As(err, &requestError) && requestError.Code == -2
+	empty := err == nil && len(torrentList.Torrents) == 0
+	if page <= 0 || !pastEnd && !empty {
+		return torrentList, page, err
+	}
+	if pastEnd {
+		// The first page always exists and has the total
+		params.Page = 0
+		if torrentList, err = c.listTorrents(ctx, params); err != nil {
+			return torrentList, 0, err
 		}
-	}`)
-	req, err := http.NewRequest("POST", config.Config.JSONRPCEndpointURL, bytes.NewBuffer(requestBody))
-	utils.CheckError(err)
-	req.Header.Add("Authorization", "Bearer "+config.Config.SecretKey)
-	resp, err := client.Do(req)
-	utils.CheckError(err)
-	body, err := ioutil.ReadAll(resp.Body)
-	utils.CheckError(err)
-	var torrentListRequestResponse torrents.TorrentListRequestResponse
-	json.Unmarshal(body, &torrentListRequestResponse)
-	updatedPageIndex := page
-	if len(torrentListRequestResponse.Result.Torrents) == 0 && page != 0 {
-		if page > 0 {
-			updatedPageIndex--
+	}
+	params.Page = lastPage(torrentList.TorrentsTotal, params.PageSize)
+	if params.Page >= page {
+		params.Page = page - 1
+	}
+	if params.Page == 0 && pastEnd {
+		return torrentList, 0, nil
+	}
+	torrentList, err = c.listTorrents(ctx, params)
+	return torrentList, params.Page, err
+}
+
+func lastPage(total, pageSize int) int {
+	if total <= 0 {
+		return 0
+	}
+	return (total - 1) / pageSize
+}
+
+// allTorrentsMaxAge is how long the whole list is reused between ticks.
+const allTorrentsMaxAge = 5 * time.Second
+
+// allTorrents returns every torrent, loading them again only when the list
+// is older than allTorrentsMaxAge or a call changed something since.
+func (c *Connection) allTorrents(ctx context.Context) ([]torrents.Torrent, error) {
+	c.allMu.Lock()
+	all, loaded, generation := c.all, c.allLoaded, c.allGeneration
+	c.allMu.Unlock()
+	if all != nil && time.Since(loaded) < allTorrentsMaxAge {
+		return all, nil
+	}
+	all, err := c.GetAllTorrents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.allMu.Lock()
+	// Dropped while loading, the list may miss the change
+	if generation == c.allGeneration {
+		c.all, c.allLoaded = all, time.Now()
+	}
+	c.allMu.Unlock()
+	return all, nil
+}
+
+func (c *Connection) dropAllTorrents() {
+	c.allMu.Lock()
+	defer c.allMu.Unlock()
+	c.all = nil
+	c.allGeneration++
+}
+
+// localTorrentList filters and sorts every torrent to apply a filter or an
+// order Porla can't, and pages the result like Porla would.
+func (c *Connection) localTorrentList(ctx context.Context, page, pageSize int, options ListOptions) (torrents.TorrentList, int, error) {
+	all, err := c.allTorrents(ctx)
+	if err != nil {
+		return torrents.TorrentList{}, page, err
+	}
+	var matching []torrents.Torrent
+	for _, torrent := range all {
+		if options.Filter.Match(torrent) {
+			matching = append(matching, torrent)
 		}
 	}
-	if torrentListRequestResponse.Error.Code == -2 {
-		if page > 0 {
-			time.Sleep(time.Millisecond * 5) // Prevent the server from interrupting the connection by adding a 25ms timeout
-			return UpdateTorrentList(page - 1)
+	torrents.Sort(matching, options.Sort)
+	if last := lastPage(len(matching), pageSize); page > last {
+		page = last
+	}
+	start := page * pageSize
+	end := start + pageSize
+	if end > len(matching) {
+		end = len(matching)
+	}
+	return torrents.TorrentList{
+		Page:          page,
+		PageSize:      pageSize,
+		Torrents:      matching[start:end],
+		TorrentsTotal: len(matching),
+	}, page, nil
+}
+
+// filterApplied reports whether Porla understood the filter query sent with
+// list, or if it rejected or ignored it.
+func filterApplied(list *jsonrpc.BatchCall, torrentList torrents.TorrentList, filter torrents.Filter) bool {
+	var requestError *jsonrpc.RequestError
+	if errors.As(list.Err, &requestError) && requestError.Code != -2 {
+		return false
+	}
+	for _, torrent := range torrentList.Torrents {
+		if !filter.Match(torrent) {
+			return false
 		}
 	}
-	return torrentListRequestResponse.Result, updatedPageIndex
+	return true
+}
+
+// orderRejected reports whether Porla answered order_by with an error.
+func orderRejected(list *jsonrpc.BatchCall) bool {
+	var requestError *jsonrpc.RequestError
+	return errors.As(list.Err, &requestError) && requestError.Code != -2
 }
 
-func AddTorrent(magnetURI, savePath string, addingMagnetLink bool) {
-	requestBody := []byte{}
+// orderApplied reports whether the page is in order. Porla may compare names
+// byte by byte rather than ignoring case like osprey does.
+func orderApplied(torrentList torrents.TorrentList, order config.SortType) bool {
+	list := torrentList.Torrents
+	if torrents.IsSorted(list, order) {
+		return true
+	}
+	return order.By == "name" && sort.SliceIsSorted(list, func(i, j int) bool {
+		if order.Descending {
+			return list[j].Name < list[i].Name
+		}
+		return list[i].Name < list[j].Name
+	})
+}
+
+// Snapshot is what the torrent list refreshes on every tick.
+type Snapshot struct {
+	TorrentList torrents.TorrentList
+	// The page that was loaded, see UpdateTorrentList
+	Page     int
+	Sessions []sessions.Session
+	// Set when a torrent was selected and its properties could be loaded
+	Properties *torrents.TorrentProperties
+}
+
+// GetSnapshot loads a page of the filtered and sorted torrent list, the
+// sessions and the properties of the selected torrent, if any, in a single
+// round trip. The filter and order are sent to Porla when possible, otherwise
+// the whole list is loaded and filtered or sorted here.
+func (c *Connection) GetSnapshot(ctx context.Context, page, pageSize int, options ListOptions, selected *torrents.Torrent) (Snapshot, error) {
+	snapshot := Snapshot{Page: page}
+	params := torrentListParams{Page: page, PageSize: pageSize}
+	serverSide := true
+	if !options.Filter.IsEmpty() {
+		query, ok := options.Filter.Query()
+		serverSide = ok && !c.noQuery.Load()
+		params.Filters = &torrentListFilters{Query: query}
+	}
+	if options.Sort.By != "" {
+		field, ok := orderBy[options.Sort.By]
+		serverSide = serverSide && ok && !c.noOrderBy.Load()
+		params.OrderBy, params.OrderByDir = field, "asc"
+		if options.Sort.Descending {
+			params.OrderByDir = "desc"
+		}
+	}
+
+	var sessionList sessions.SessionList
+	var properties torrents.TorrentProperties
+	calls := []*jsonrpc.BatchCall{
+		{Method: "sessions.list", Result: &sessionList},
+	}
+	if selected != nil {
+		calls = append(calls, &jsonrpc.BatchCall{
+			Method: "torrents.properties.get",
+			Params: infoHashParams{InfoHash: selected.InfoHash},
+			Result: &properties,
+		})
+	}
+	list := &jsonrpc.BatchCall{Method: "torrents.list", Params: params, Result: &snapshot.TorrentList}
+	if serverSide {
+		calls = append(calls, list)
+	}
+	if err := c.batch(ctx, calls); err != nil {
+		return snapshot, err
+	}
+	// Porla versions without sessions.list still get the list
+	if calls[0].Err == nil {
+		snapshot.Sessions = sessionList.Sessions
+	}
+	if selected != nil && calls[1].Err == nil {
+		snapshot.Properties = &properties
+	}
+
+	if serverSide && params.Filters != nil && !filterApplied(list, snapshot.TorrentList, options.Filter) {
+		c.noQuery.Store(true)
+		serverSide = false
+	}
+	if serverSide && params.OrderBy != "" {
+		switch {
+		case orderRejected(list):
+			c.noOrderBy.Store(true)
+			serverSide = false
+		case !orderApplied(snapshot.TorrentList, options.Sort):
+			// Maybe ignored, order_by is tried again on the next tick
+			serverSide = false
+		}
+	}
+	var err error
+	switch {
+	case !serverSide:
+		snapshot.TorrentList, snapshot.Page, err = c.localTorrentList(ctx, page, pageSize, options)
+	case list.Err != nil || page > 0 && len(snapshot.TorrentList.Torrents) == 0:
+		snapshot.TorrentList, snapshot.Page, err = c.updateTorrentList(ctx, params)
+	}
+	return snapshot, err
+}
+
+func (c *Connection) AddTorrent(ctx context.Context, magnetURI, savePath string, addingMagnetLink bool) error {
+	params := torrentAddParams{
+		SavePath: savePath,
+		Metadata: torrentAddMetadata{
+			Source: "osprey",
+		},
+	}
 	if addingMagnetLink {
-		requestBody = []byte(`{
-			"jsonrpc": "2.0",
-			"method": "torrents.add",
-			"params": {
-				"magnet_uri":"` + magnetURI + `",
-				"save_path":"` + savePath + `",
-				"metadata": {
-					"source": "osprey"
-				}
-			}
-		}`)
+		params.MagnetURI = magnetURI
 	} else {
-		f, err := os.Open(magnetURI)
-		utils.CheckError(err)
-
-		// Read entire JPG into byte slice.
-		reader := bufio.NewReader(f)
-		content, err := ioutil.ReadAll(reader)
-		utils.CheckError(err)
-
-		// Encode as base64.
-		encoded := base64.StdEncoding.EncodeToString(content)
-		requestBody = []byte(`{
-			"jsonrpc": "2.0",
-			"method": "torrents.add",
-			"params": {
-				"ti":"` + encoded + `",
-				"save_path":"` + savePath + `",
-				"metadata": {
-					"source": "osprey"
-				}
-			}
-		}`)
-	}
-	req, err := http.NewRequest("POST", config.Config.JSONRPCEndpointURL, bytes.NewBuffer(requestBody))
-	utils.CheckError(err)
-	req.Header.Add("Authorization", "Bearer "+config.Config.SecretKey)
-	_, err = client.Do(req)
-	utils.CheckError(err)
-}
-
-func DeleteTorrent(torrent torrents.Torrent, keepData bool) {
-	removeDataJSON, err := json.Marshal(!keepData)
-	utils.CheckError(err)
-	requestBody := []byte(`{
-		"jsonrpc": "2.0",
-		"method": "torrents.remove",
-		"params": {
-			"info_hashes":[` + getMarshalledInfoHash(torrent) + `],
-			"remove_data":` + string(removeDataJSON) + `
-		}
-	}`)
-	req, err := http.NewRequest("POST", config.Config.JSONRPCEndpointURL, bytes.NewBuffer(requestBody))
-	utils.CheckError(err)
-	req.Header.Add("Authorization", "Bearer "+config.Config.SecretKey)
-	_, err = client.Do(req)
-	utils.CheckError(err)
-}
-
-func PauseResumeTorrent(torrent torrents.Torrent) {
-	method := "torrents.pause"
+		content, err := os.ReadFile(magnetURI)
+		if err != nil {
+			return err
+		}
+		params.TorrentInfo = base64.StdEncoding.EncodeToString(content)
+	}
+	return c.call(ctx, "torrents.add", params, nil)
+}
+
+func (c *Connection) DeleteTorrent(ctx context.Context, torrent torrents.Torrent, keepData bool) error {
+	return c.DeleteTorrents(ctx, []torrents.Torrent{torrent}, keepData)
+}
+
+// DeleteTorrents removes all the torrents in a single call.
+func (c *Connection) DeleteTorrents(ctx context.Context, torrentList []torrents.Torrent, keepData bool) error {
+	infoHashes := make([]torrents.InfoHash, len(torrentList))
+	for i, torrent := range torrentList {
+		infoHashes[i] = torrent.InfoHash
+	}
+	return c.call(ctx, "torrents.remove", torrentRemoveParams{
+		InfoHashes: infoHashes,
+		RemoveData: !keepData,
+	}, nil)
+}
+
+func (c *Connection) PauseResumeTorrent(ctx context.Context, torrent torrents.Torrent) error {
 	if torrents.IsPaused(torrent.Flags) {
-		method = "torrents.resume"
-	}
-	requestBody := []byte(`{
-		"jsonrpc": "2.0",
-		"method": "` + method + `",
-		"params": {
-			"info_hash":` + getMarshalledInfoHash(torrent) + `
-		}
-	}`)
-	req, err := http.NewRequest("POST", config.Config.JSONRPCEndpointURL, bytes.NewBuffer(requestBody))
-	utils.CheckError(err)
-	req.Header.Add("Authorization", "Bearer "+config.Config.SecretKey)
-	_, err = client.Do(req)
-	utils.CheckError(err)
-}
-
-func MoveTorrent(torrent torrents.Torrent, newPath string) {
-	requestBody := []byte(`{
-		"jsonrpc": "2.0",
-		"method": "torrents.move",
-		"params": {
-			"info_hash":` + getMarshalledInfoHash(torrent) + `,
-			"path": "` + newPath + `"
-		}
-	}`)
-	req, err := http.NewRequest("POST", config.Config.JSONRPCEndpointURL, bytes.NewBuffer(requestBody))
-	utils.CheckError(err)
-	req.Header.Add("Authorization", "Bearer "+config.Config.SecretKey)
-	_, err = client.Do(req)
-	utils.CheckError(err)
-}
-
-func GetTorrentProperties(torrent torrents.Torrent) torrents.TorrentProperties {
-	requestBody := []byte(`{
-		"jsonrpc": "2.0",
-		"method": "torrents.properties.get",
-		"params": {
-			"info_hash":` + getMarshalledInfoHash(torrent) + `
-		}
-	}`)
-	req, err := http.NewRequest("POST", config.Config.JSONRPCEndpointURL, bytes.NewBuffer(requestBody))
-	utils.CheckError(err)
-	req.Header.Add("Authorization", "Bearer "+config.Config.SecretKey)
-	resp, err := client.Do(req)
-	utils.CheckError(err)
-	body, err := ioutil.ReadAll(resp.Body)
-	utils.CheckError(err)
-	var torrentPropertiesRequestResponse torrents.TorrentPropertiesRequestResponse
-	json.Unmarshal(body, &torrentPropertiesRequestResponse)
-	return torrentPropertiesRequestResponse.Result
-}
-
-func SetTorrentProperties(torrent torrents.Torrent, torrentPropertiesSetData torrents.TorrentPropertiesSetData) {
+		return c.ResumeTorrent(ctx, torrent)
+	}
+	return c.PauseTorrent(ctx, torrent)
+}
+
+func (c *Connection) PauseTorrent(ctx context.Context, torrent torrents.Torrent) error {
+	return c.call(ctx, "torrents.pause", infoHashParams{
+		InfoHash: torrent.InfoHash,
+	}, nil)
+}
+
+func (c *Connection) ResumeTorrent(ctx context.Context, torrent torrents.Torrent) error {
+	return c.call(ctx, "torrents.resume", infoHashParams{
+		InfoHash: torrent.InfoHash,
+	}, nil)
+}
+
+// RecheckTorrent makes Porla check the downloaded data of the torrent again.
+func (c *Connection) RecheckTorrent(ctx context.Context, torrent torrents.Torrent) error {
+	return c.call(ctx, "torrents.recheck", infoHashParams{
+		InfoHash: torrent.InfoHash,
+	}, nil)
+}
+
+// QueueMove is a move of a torrent in the download queue.
+type QueueMove string
+
+const (
+	QueueUp     QueueMove = "up"
+	QueueDown   QueueMove = "down"
+	QueueTop    QueueMove = "top"
+	QueueBottom QueueMove = "bottom"
+)
+
+func (c *Connection) MoveInQueue(ctx context.Context, torrent torrents.Torrent, move QueueMove) error {
+	return c.call(ctx, "torrents.queue."+string(move), infoHashParams{
+		InfoHash: torrent.InfoHash,
+	}, nil)
+}
+
+func (c *Connection) MoveTorrent(ctx context.Context, torrent torrents.Torrent, newPath string) error {
+	return c.call(ctx, "torrents.move", torrentMoveParams{
+		InfoHash: torrent.InfoHash,
+		Path:     newPath,
+	}, nil)
+}
+
+func (c *Connection) GetTorrentProperties(ctx context.Context, torrent torrents.Torrent) (torrents.TorrentProperties, error) {
+	var torrentProperties torrents.TorrentProperties
+	err := c.call(ctx, "torrents.properties.get", infoHashParams{
+		InfoHash: torrent.InfoHash,
+	}, &torrentProperties)
+	return torrentProperties, err
+}
+
+func (c *Connection) SetTorrentProperties(ctx context.Context, torrent torrents.Torrent, torrentPropertiesSetData torrents.TorrentPropertiesSetData) error {
 	set_flags := 0
 	if torrentPropertiesSetData.IsAutomaticallyManaged {
 		set_flags |= 1 << 5
@@ -194,38 +661,39 @@ func SetTorrentProperties(torrent torrents.Torrent, torrentPropertiesSetData tor
 	if !torrentPropertiesSetData.IsSequenciallyDownloading {
 		unset_flags |= 1 << 9
 	}
-	requestBody := []byte(`{
-		"jsonrpc": "2.0",
-		"method": "torrents.properties.set",
-		"params": {
-			"info_hash":` + getMarshalledInfoHash(torrent) + `,
-			"auto_managed": ` + marshallBool(torrentPropertiesSetData.IsAutomaticallyManaged) + `,
-			"download_limit": ` + torrentPropertiesSetData.DownloadLimit + `,
-			"max_connections": ` + torrentPropertiesSetData.MaxConnections + `,
-			"max_uploads": ` + torrentPropertiesSetData.MaxUploads + `,
-			"sequential_download": ` + marshallBool(torrentPropertiesSetData.IsSequenciallyDownloading) + `,
-			"set_flags": ` + strconv.Itoa(set_flags) + `,
-			"unset_flags": ` + strconv.Itoa(unset_flags) + `,
-			"upload_limit": ` + torrentPropertiesSetData.UploadLimit + `
-		}
-	}`)
-	req, err := http.NewRequest("POST", config.Config.JSONRPCEndpointURL, bytes.NewBuffer(requestBody))
-	utils.CheckError(err)
-	req.Header.Add("Authorization", "Bearer "+config.Config.SecretKey)
-	_, err = client.Do(req)
-	utils.CheckError(err)
-}
-
-func marshallBool(i bool) string {
-	o, err := json.Marshal(i)
-	utils.CheckError(err)
-	return string(o)
-}
-
-func getMarshalledInfoHash(torrent torrents.Torrent) string {
-	marshalledInfoHash, err := json.Marshal(torrent.InfoHash)
-	utils.CheckError(err)
-	marshalledInfoHashWithNull := strings.Replace(string(marshalledInfoHash), "\"\"", "null", -1)
-	utils.CheckError(err)
-	return marshalledInfoHashWithNull
+	params := torrentPropertiesSetParams{
+		InfoHash:           torrent.InfoHash,
+		AutoManaged:        torrentPropertiesSetData.IsAutomaticallyManaged,
+		SequentialDownload: torrentPropertiesSetData.IsSequenciallyDownloading,
+		SetFlags:           set_flags,
+		UnsetFlags:         unset_flags,
+	}
+	var err error
+	if params.DownloadLimit, err = parseOptionalInt("download_limit", torrentPropertiesSetData.DownloadLimit); err != nil {
+		return err
+	}
+	if params.MaxConnections, err = parseOptionalInt("max_connections", torrentPropertiesSetData.MaxConnections); err != nil {
+		return err
+	}
+	if params.MaxUploads, err = parseOptionalInt("max_uploads", torrentPropertiesSetData.MaxUploads); err != nil {
+		return err
+	}
+	if params.UploadLimit, err = parseOptionalInt("upload_limit", torrentPropertiesSetData.UploadLimit); err != nil {
+		return err
+	}
+	return c.call(ctx, "torrents.properties.set", params, nil)
+}
+
+// Settings inputs that are left empty are omitted from the request so Porla
+// keeps the current value.
+func parseOptionalInt(field, s string) (*int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q is not a number", field, s)
+	}
+	return &i, nil
 }
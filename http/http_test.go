@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"osprey/config"
+	"osprey/data/torrents"
+)
+
+// newTestConnection connects to a server answering every request with
+// status and returns the methods it was sent, one entry per POST.
+func newTestConnection(t *testing.T, status int, timeouts map[string]time.Duration) (*Connection, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var posts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct{ Method string }
+		json.NewDecoder(r.Body).Decode(&request)
+		mu.Lock()
+		posts = append(posts, request.Method)
+		mu.Unlock()
+		if status == 0 {
+			// Never answers, until the client gives up
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	connection := Connect(config.ProfileType{JSONRPCEndpointURL: server.URL, SecretKey: "token", Timeouts: timeouts})
+	return connection, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), posts...)
+	}
+}
+
+var testTorrent = torrents.Torrent{InfoHash: torrents.InfoHash{"aaaa", ""}}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		call      func(context.Context, *Connection) error
+		wantPosts int
+	}{
+		{"torrents.add on 503", http.StatusServiceUnavailable, func(ctx context.Context, c *Connection) error {
+			return c.AddTorrent(ctx, "magnet:?xt=urn:btih:aaaa", "/data", true)
+		}, 1},
+		{"torrents.remove on 503", http.StatusServiceUnavailable, func(ctx context.Context, c *Connection) error {
+			return c.DeleteTorrent(ctx, testTorrent, true)
+		}, 1},
+		{"torrents.pause on 503", http.StatusServiceUnavailable, func(ctx context.Context, c *Connection) error {
+			return c.PauseTorrent(ctx, testTorrent)
+		}, 1},
+		{"torrents.list on 503", http.StatusServiceUnavailable, func(ctx context.Context, c *Connection) error {
+			_, err := c.GetTorrentList(ctx, 0, 10)
+			return err
+		}, maxAttempts},
+		{"sys.versions on 429", http.StatusTooManyRequests, func(ctx context.Context, c *Connection) error {
+			_, err := c.GetVersions(ctx)
+			return err
+		}, maxAttempts},
+		{"read-only batch on 503", http.StatusServiceUnavailable, func(ctx context.Context, c *Connection) error {
+			_, err := c.GetTorrentDetails(ctx, testTorrent, DetailsParts{Properties: true, Files: true})
+			return err
+		}, maxAttempts},
+		{"torrents.list on 401", http.StatusUnauthorized, func(ctx context.Context, c *Connection) error {
+			_, err := c.GetTorrentList(ctx, 0, 10)
+			return err
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connection, posts := newTestConnection(t, tt.status, nil)
+			if err := tt.call(context.Background(), connection); err == nil {
+				t.Fatal("no error")
+			}
+			if got := posts(); len(got) != tt.wantPosts {
+				t.Errorf("got %d POSTs %v, want %d", len(got), got, tt.wantPosts)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenCancelled(t *testing.T) {
+	connection, posts := newTestConnection(t, http.StatusServiceUnavailable, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := connection.GetTorrentList(ctx, 0, 10); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if got := posts(); len(got) != 0 {
+		t.Errorf("got %d POSTs, want none", len(got))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 1; attempt < maxAttempts; attempt++ {
+		delay := retryDelay << (attempt - 1)
+		for i := 0; i < 100; i++ {
+			if got := backoff(attempt); got < delay/2 || got >= delay {
+				t.Fatalf("attempt %d: got %v, want within [%v, %v)", attempt, got, delay/2, delay)
+			}
+		}
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	timeouts := map[string]time.Duration{
+		config.DefaultTimeoutKey: 50 * time.Millisecond,
+		"torrents.add":           100 * time.Millisecond,
+	}
+	tests := []struct {
+		name      string
+		call      func(context.Context, *Connection) error
+		wantPosts int
+	}{
+		{"default timeout", func(ctx context.Context, c *Connection) error {
+			_, err := c.GetTorrentList(ctx, 0, 10)
+			return err
+		}, maxAttempts},
+		{"method timeout", func(ctx context.Context, c *Connection) error {
+			return c.AddTorrent(ctx, "magnet:?xt=urn:btih:aaaa", "/data", true)
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connection, posts := newTestConnection(t, 0, timeouts)
+			start := time.Now()
+			err := tt.call(context.Background(), connection)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("the call took %v", elapsed)
+			}
+			if got := posts(); len(got) != tt.wantPosts {
+				t.Errorf("got %d POSTs, want %d", len(got), tt.wantPosts)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		timeouts map[string]time.Duration
+		method   string
+		want     time.Duration
+	}{
+		{nil, "torrents.list", DefaultTimeout},
+		{map[string]time.Duration{config.DefaultTimeoutKey: time.Second}, "torrents.list", time.Second},
+		{map[string]time.Duration{config.DefaultTimeoutKey: time.Second, "torrents.add": time.Minute}, "torrents.add", time.Minute},
+		{map[string]time.Duration{"torrents.add": 0}, "torrents.add", 0},
+	}
+	for _, tt := range tests {
+		connection := Connect(config.ProfileType{Timeouts: tt.timeouts})
+		if got := connection.Timeout(tt.method); got != tt.want {
+			t.Errorf("%v, %s: got %v, want %v", tt.timeouts, tt.method, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"osprey/config"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const sshDialTimeout = 15 * time.Second
+
+// sshTunnel forwards connections to Porla through one SSH connection, which
+// is opened again when it drops.
+type sshTunnel struct {
+	settings config.SSHType
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+var (
+	sshTunnelsMu sync.Mutex
+	// Profiles with the same SSH settings share their connection, and
+	// reconnecting to a profile reuses it.
+	sshTunnels = map[config.SSHType]*sshTunnel{}
+)
+
+func getSSHTunnel(settings config.SSHType) *sshTunnel {
+	sshTunnelsMu.Lock()
+	defer sshTunnelsMu.Unlock()
+	tunnel, ok := sshTunnels[settings]
+	if !ok {
+		tunnel = &sshTunnel{settings: settings}
+		sshTunnels[settings] = tunnel
+	}
+	return tunnel
+}
+
+// dial opens a connection to address on the remote host. A failure on an
+// existing SSH connection is retried once on a new one, in case it dropped
+// without being noticed yet.
+func (t *sshTunnel) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	client, fresh, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := client.Dial(network, address)
+	if err == nil || fresh {
+		return conn, err
+	}
+	t.drop(client)
+	if client, _, err = t.connect(ctx); err != nil {
+		return nil, err
+	}
+	return client.Dial(network, address)
+}
+
+func (t *sshTunnel) connect(ctx context.Context) (*ssh.Client, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil {
+		return t.client, false, nil
+	}
+	client, err := dialSSH(ctx, t.settings)
+	if err != nil {
+		return nil, false, err
+	}
+	t.client = client
+	go func() {
+		client.Wait()
+		t.drop(client)
+	}()
+	return client, true, nil
+}
+
+func (t *sshTunnel) drop(client *ssh.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == client {
+		t.client = nil
+	}
+	client.Close()
+}
+
+func dialSSH(ctx context.Context, settings config.SSHType) (*ssh.Client, error) {
+	address := settings.Host
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "22")
+	}
+	username := settings.User
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("ssh.user: %w", err)
+		}
+		username = current.Username
+	}
+	hostKeyCallback, err := sshHostKeyCallback(settings)
+	if err != nil {
+		return nil, err
+	}
+	auth, agentConn, err := sshAuthMethods(settings)
+	if err != nil {
+		return nil, err
+	}
+	// The agent is only needed for the handshake
+	if agentConn != nil {
+		defer agentConn.Close()
+	}
+	sshConfig := &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshDialTimeout,
+	}
+
+	var dialer net.Dialer
+	ctx, cancel := context.WithTimeout(ctx, sshDialTimeout)
+	defer cancel()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: %w", err)
+	}
+	// sshConfig.Timeout only applies to ssh.Dial, a host that never
+	// finishes the handshake would otherwise hold the tunnel forever
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	c, channels, requests, err := ssh.NewClientConn(conn, address, sshConfig)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ssh.NewClient(c, channels, requests), nil
+}
+
+// Host keys are always checked, against ~/.ssh/known_hosts unless another
+// file is given.
+func sshHostKeyCallback(settings config.SSHType) (ssh.HostKeyCallback, error) {
+	path := settings.KnownHosts
+	if path == "" {
+		path = "~/.ssh/known_hosts"
+	}
+	path, err := config.ExpandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("ssh.known_hosts: %w", err)
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyError *knownhosts.KeyError
+		if errors.As(err, &keyError) && len(keyError.Want) == 0 {
+			return fmt.Errorf("ssh: %s is not in %s, connect to it once with ssh to add it", hostname, path)
+		}
+		return err
+	}, nil
+}
+
+// The SSH agent is used when running, along with identity_file or, without
+// it, the default keys that aren't protected by a passphrase. The connection
+// to the agent, if any, must be closed once the handshake is done.
+func sshAuthMethods(settings config.SSHType) ([]ssh.AuthMethod, net.Conn, error) {
+	var signers []ssh.Signer
+	if settings.IdentityFile != "" {
+		path, err := config.ExpandHome(settings.IdentityFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		signer, err := loadSSHKey(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ssh.identity_file: %w", err)
+		}
+		signers = append(signers, signer)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			if signer, err := loadSSHKey(filepath.Join(home, ".ssh", name)); err == nil {
+				signers = append(signers, signer)
+			}
+		}
+	}
+
+	var agentConn net.Conn
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			agentConn = conn
+		}
+	}
+	if agentConn == nil && len(signers) == 0 {
+		return nil, nil, errors.New("ssh: no key to authenticate with, set ssh.identity_file or add a key to the SSH agent")
+	}
+	// The client tries a single publickey method, so the keys of the agent
+	// and the files are given together, the agent's first.
+	var agentClient agent.ExtendedAgent
+	if agentConn != nil {
+		agentClient = agent.NewClient(agentConn)
+	}
+	return []ssh.AuthMethod{ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+		if agentClient == nil {
+			return signers, nil
+		}
+		agentSigners, err := agentClient.Signers()
+		if err != nil {
+			return signers, nil
+		}
+		return append(agentSigners, signers...), nil
+	})}, agentConn, nil
+}
+
+func loadSSHKey(path string) (ssh.Signer, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(pem)
+	var passphraseMissing *ssh.PassphraseMissingError
+	if errors.As(err, &passphraseMissing) {
+		return nil, fmt.Errorf("%s is protected by a passphrase, add it to the SSH agent instead", path)
+	}
+	return signer, err
+}
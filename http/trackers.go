@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+
+	"osprey/data/torrents"
+)
+
+type trackersAddParams struct {
+	InfoHash torrents.InfoHash `json:"info_hash"`
+	URL      string            `json:"url"`
+	Tier     int               `json:"tier"`
+}
+
+type trackerEntry struct {
+	URL  string `json:"url"`
+	Tier int    `json:"tier"`
+}
+
+type trackersReplaceParams struct {
+	InfoHash torrents.InfoHash `json:"info_hash"`
+	Trackers []trackerEntry    `json:"trackers"`
+}
+
+// AddTracker adds an announce URL to the torrent, in the given tier.
+func (c *Connection) AddTracker(ctx context.Context, torrent torrents.Torrent, url string, tier int) error {
+	return c.call(ctx, "torrents.trackers.add", trackersAddParams{
+		InfoHash: torrent.InfoHash,
+		URL:      url,
+		Tier:     tier,
+	}, nil)
+}
+
+// ReplaceTrackers makes trackers the whole tracker list of the torrent, which
+// is how trackers are edited and removed.
+func (c *Connection) ReplaceTrackers(ctx context.Context, torrent torrents.Torrent, trackers []torrents.Tracker) error {
+	params := trackersReplaceParams{InfoHash: torrent.InfoHash, Trackers: []trackerEntry{}}
+	for _, tracker := range trackers {
+		params.Trackers = append(params.Trackers, trackerEntry{URL: tracker.URL, Tier: tracker.Tier})
+	}
+	return c.call(ctx, "torrents.trackers.replace", params, nil)
+}
+
+// ReannounceTorrent announces the torrent to all its trackers right away.
+func (c *Connection) ReannounceTorrent(ctx context.Context, torrent torrents.Torrent) error {
+	return c.call(ctx, "torrents.reannounce", infoHashParams{
+		InfoHash: torrent.InfoHash,
+	}, nil)
+}
+
+// ScrapeTorrent asks the trackers of the torrent for its seeds and peers.
+func (c *Connection) ScrapeTorrent(ctx context.Context, torrent torrents.Torrent) error {
+	return c.call(ctx, "torrents.scrape", infoHashParams{
+		InfoHash: torrent.InfoHash,
+	}, nil)
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"osprey/config"
+	"time"
+)
+
+// unixSocketPath is where requests are sent on the socket of unix://
+// endpoints, the path Porla serves JSON-RPC on over TCP.
+const unixSocketPath = "/api/v1/jsonrpc"
+
+const (
+	idleConnsPerHost = 4
+	idleConnTimeout  = 2 * time.Minute
+)
+
+// newHTTPClient returns the client for the profile and the URL to post
+// requests to, which differs from the configured endpoint for Unix sockets.
+func newHTTPClient(profile config.ProfileType) (*http.Client, string, error) {
+	endpoint := profile.JSONRPCEndpointURL
+	tlsConfig, err := newTLSConfig(profile.TLS)
+	if err != nil {
+		return nil, endpoint, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	// The TUI calls Porla every second and the dashboard polls all profiles,
+	// keep enough connections open between ticks to avoid new handshakes.
+	transport.MaxIdleConnsPerHost = idleConnsPerHost
+	transport.IdleConnTimeout = idleConnTimeout
+
+	var dial func(ctx context.Context, network, address string) (net.Conn, error)
+	if profile.SSH.Host != "" {
+		dial = getSSHTunnel(profile.SSH).dial
+	}
+
+	if u, err := url.Parse(endpoint); err == nil && u.Scheme == "unix" {
+		// The host of the URL is only used in the Host header
+		socket := u.Path
+		if dial == nil {
+			var dialer net.Dialer
+			dial = dialer.DialContext
+		}
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dial(ctx, "unix", socket)
+		}
+		endpoint = "http://localhost" + unixSocketPath
+	} else if dial != nil {
+		// The endpoint is resolved on the SSH host, where Porla usually only
+		// listens on the loopback interface.
+		transport.Proxy = nil
+		transport.DialContext = dial
+	} else {
+		proxyURL, err := config.ProxyURL(profile)
+		if err != nil {
+			return nil, endpoint, err
+		}
+		if proxyURL != nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: redirectPolicyFunc,
+	}, endpoint, nil
+}
+
+func newTLSConfig(settings config.TLSType) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         settings.ServerName,
+		InsecureSkipVerify: settings.InsecureSkipVerify != nil && *settings.InsecureSkipVerify,
+	}
+	if settings.CAFile != "" {
+		path, err := config.ExpandHome(settings.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("tls.ca_file: %w", err)
+		}
+		// The CA is trusted along with the system ones, so a profile can
+		// still reach servers with public certificates.
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls.ca_file: no certificate found in %s", path)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if settings.CertFile != "" {
+		certFile, err := config.ExpandHome(settings.CertFile)
+		if err != nil {
+			return nil, err
+		}
+		keyFile, err := config.ExpandHome(settings.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls.cert_file: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
@@ -14,6 +14,19 @@ type i18nTorrentStates struct {
 	Unknown             string
 }
 
+type i18nSortFields struct {
+	Name          string
+	Size          string
+	Progress      string
+	DownloadRate  string
+	UploadRate    string
+	Peers         string
+	Seeds         string
+	QueuePosition string
+	ETA           string
+	State         string
+}
+
 type i18nKeybinds struct {
 	YesKeybind                 string
 	NoKeybind                  string
@@ -31,6 +44,28 @@ type i18nKeybinds struct {
 	TorrentSettingsKeybind     string
 	ToggleOptionKeybind        string
 	NinjaModeKeybind           string
+	DismissErrorKeybind        string
+	RetryNowKeybind            string
+	SwitchProfileKeybind       string
+	DashboardKeybind           string
+	TestAndSaveKeybind         string
+	LogInKeybind               string
+	QuitEscKeybind             string
+	MarkTorrentsKeybind        string
+	ClearMarksKeybind          string
+	FilterKeybind              string
+	ApplyFilterKeybind         string
+	ClearFilterKeybind         string
+	SortKeybind                string
+	DetailsKeybind             string
+	ChangeTabKeybind           string
+	ToggleFolderKeybind        string
+	FilePriorityKeybind        string
+	AddPeerKeybind             string
+	EditTrackersKeybind        string
+	ReannounceScrapeKeybind    string
+	RecheckReannounceKeybind   string
+	QueueKeybind               string
 }
 
 type I18n struct {
@@ -41,12 +76,19 @@ type I18n struct {
 	SavePath          string
 
 	DeletingTorrentName string
+	DeletingTorrents    string
 	KeepDataQuestion    string
 
+	RecheckingTorrentName string
+	RecheckingTorrents    string
+	RecheckQuestion       string
+
 	MovingTorrentName string
+	MovingTorrents    string
 	NewSavePath       string
 
 	TorrentSettingsForTorrentName string
+	TorrentSettingsForTorrents    string
 	AutomaticallyManaged          string
 	SequentialDownload            string
 	DownloadLimit                 string
@@ -57,6 +99,11 @@ type I18n struct {
 
 	TorrentsActive string
 	Torrent        string
+	TorrentsMarked string
+
+	FilterPlaceHolder string
+	FilterHint        string
+	FilterActive      string
 
 	PageInfo string
 
@@ -69,9 +116,83 @@ type I18n struct {
 
 	TorrentStates i18nTorrentStates
 
+	SortFields i18nSortFields
+	SortedBy   string
+
+	GeneralTab       string
+	FilesTab         string
+	PeersTab         string
+	TrackersTab      string
+	InfoHashV1       string
+	InfoHashV2       string
+	Downloaded       string
+	DownloadedValue  string
+	QueuePosition    string
+	Ratio            string
+	AddedOn          string
+	Unlimited        string
+	Unknown          string
+	None             string
+	NoFiles          string
+	PrioritySkip     string
+	PriorityLow      string
+	PriorityNormal   string
+	PriorityHigh     string
+	PriorityMixed    string
+	NoPeers          string
+	NoTrackers       string
+	PeerAddress      string
+	PeerClient       string
+	PeerProgress     string
+	PeerDownloadRate string
+	PeerUploadRate   string
+	PeerFlags        string
+	PeerFlagsLegend  string
+	AddPeer          string
+	TrackerTier      string
+	TrackerStatus    string
+	TrackerSeeds     string
+	TrackerPeers     string
+	NextAnnounce     string
+	AddTracker       string
+	TrackerURL       string
+	TrackerWorking   string
+	TrackerUpdating  string
+	TrackerFails     string
+
 	SeeYouLater              string
 	ErrorNonExistantView     string
 	ConnectingToPorlaBackend string
+	ErrorBanner              string
+	LoadingTorrentsFromPorla string
+	ConnectionFailed         string
+	AuthenticationFailed     string
+	RetryingIn               string
+	SwitchProfile            string
+	CurrentProfile           string
+	Dashboard                string
+	ServerConnecting         string
+	SessionPaused            string
+	SessionNotListening      string
+
+	SetupWelcome         string
+	SetupHint            string
+	Endpoint             string
+	SecretKey            string
+	SecretKeyHint        string
+	SecretKeyPlaceHolder string
+	SecretKeyRequired    string
+	TestingConnection    string
+	SetupSaved           string
+
+	LoginTitle          string
+	LoginHint           string
+	Username            string
+	Password            string
+	UsernamePlaceHolder string
+	PasswordPlaceHolder string
+	WrongCredentials    string
+	LoggingIn           string
 }
 
 var English = I18n{
@@ -82,12 +203,19 @@ var English = I18n{
 	SavePath:          "Save path",
 
 	DeletingTorrentName: "Deleting %s",
+	DeletingTorrents:    "Deleting %d torrents",
 	KeepDataQuestion:    "Keep data?",
 
+	RecheckingTorrentName: "Rechecking %s",
+	RecheckingTorrents:    "Rechecking %d torrents",
+	RecheckQuestion:       "%s of data will be read from the disk again, which can take a while. Recheck?",
+
 	MovingTorrentName: "Moving %s",
+	MovingTorrents:    "Moving %d torrents",
 	NewSavePath:       "New save path",
 
 	TorrentSettingsForTorrentName: "Torrent settings for %s",
+	TorrentSettingsForTorrents:    "Torrent settings for %d torrents",
 	AutomaticallyManaged:          "Automatically managed",
 	SequentialDownload:            "Sequential download",
 	DownloadLimit:                 "Download limit",
@@ -98,6 +226,11 @@ var English = I18n{
 
 	TorrentsActive: "%s active",
 	Torrent:        "torrent",
+	TorrentsMarked: "%d marked",
+
+	FilterPlaceHolder: "name, /regex/, state:, path:, ratio:, size:, is:error",
+	FilterHint:        "e.g. ubuntu state:seeding,paused path:/data ratio:<1 size:1GB..10GB",
+	FilterActive:      "filter: %s",
 
 	PageInfo: "Page %d/%d (max %d results)",
 
@@ -122,6 +255,28 @@ var English = I18n{
 		ToggleMagnetTorrentKeybind: "tab: toggle magnet/.torrent",
 		ToggleOptionKeybind:        "space: toggle option",
 		NinjaModeKeybind:           "n: toggle ninja mode",
+		DismissErrorKeybind:        "esc: dismiss error",
+		RetryNowKeybind:            "r: retry now",
+		SwitchProfileKeybind:       "P: switch profile",
+		DashboardKeybind:           "D: all servers",
+		TestAndSaveKeybind:         "enter: test and save",
+		LogInKeybind:               "enter: log in",
+		QuitEscKeybind:             "esc: quit",
+		MarkTorrentsKeybind:        "space/V/*: mark one/range/all",
+		ClearMarksKeybind:          "esc: clear marks",
+		FilterKeybind:              "/: filter",
+		ApplyFilterKeybind:         "enter: apply filter",
+		ClearFilterKeybind:         "esc: clear filter",
+		SortKeybind:                "o/O: sort/reverse",
+		DetailsKeybind:             "enter: details",
+		ChangeTabKeybind:           "tab/shift+tab: change tab",
+		ToggleFolderKeybind:        "enter: open/close folder",
+		FilePriorityKeybind:        "+/-: raise/lower priority",
+		AddPeerKeybind:             "a: add peer",
+		EditTrackersKeybind:        "a/e/x: add/edit/remove tracker",
+		ReannounceScrapeKeybind:    "R/S: reannounce/scrape",
+		RecheckReannounceKeybind:   "c/R: recheck/reannounce",
+		QueueKeybind:               "[/]/{/}: queue up/down/top/bottom",
 	},
 
 	TorrentStates: i18nTorrentStates{
@@ -138,9 +293,94 @@ var English = I18n{
 		Unknown:             "unknown",
 	},
 
+	SortFields: i18nSortFields{
+		Name:          "name",
+		Size:          "size",
+		Progress:      "progress",
+		DownloadRate:  "download rate",
+		UploadRate:    "upload rate",
+		Peers:         "peers",
+		Seeds:         "seeds",
+		QueuePosition: "queue position",
+		ETA:           "ETA",
+		State:         "state",
+	},
+	SortedBy: "sorted by %s",
+
+	GeneralTab:       "General",
+	FilesTab:         "Files",
+	PeersTab:         "Peers",
+	TrackersTab:      "Trackers",
+	InfoHashV1:       "Info hash v1",
+	InfoHashV2:       "Info hash v2",
+	Downloaded:       "Downloaded",
+	DownloadedValue:  "%s of %s",
+	QueuePosition:    "Queue position",
+	Ratio:            "Ratio",
+	AddedOn:          "Added",
+	Unlimited:        "unlimited",
+	Unknown:          "unknown",
+	None:             "none",
+	NoFiles:          "No files yet, the metadata is still being downloaded.",
+	PrioritySkip:     "skip",
+	PriorityLow:      "low",
+	PriorityNormal:   "normal",
+	PriorityHigh:     "high",
+	PriorityMixed:    "mixed",
+	NoPeers:          "No peers connected.",
+	NoTrackers:       "No trackers.",
+	PeerAddress:      "Address",
+	PeerClient:       "Client",
+	PeerProgress:     "Progress",
+	PeerDownloadRate: "Down",
+	PeerUploadRate:   "Up",
+	PeerFlags:        "Flags",
+	PeerFlagsLegend:  "I: incoming, E: encrypted, P: µTP, S: seed, X: snubbed",
+	AddPeer:          "Add peer:",
+	TrackerTier:      "Tier",
+	TrackerStatus:    "Status",
+	TrackerSeeds:     "Seeds",
+	TrackerPeers:     "Peers",
+	NextAnnounce:     "Next announce",
+	AddTracker:       "Add tracker:",
+	TrackerURL:       "Tracker URL:",
+	TrackerWorking:   "working",
+	TrackerUpdating:  "announcing",
+	TrackerFails:     "%d failures",
+
 	SeeYouLater:              "See you later!",
 	ErrorNonExistantView:     "Error: Non existant view called.",
 	ConnectingToPorlaBackend: "Establishing connection to Porla backend.",
+	ErrorBanner:              "Error: %s",
+	LoadingTorrentsFromPorla: "Connected to Porla %s, loading torrents.",
+	ConnectionFailed:         "Connection failed: %s",
+	AuthenticationFailed:     "authentication failed, check the secret key",
+	RetryingIn:               "Retrying in %s (attempt %d).",
+	SwitchProfile:            "Switch profile",
+	CurrentProfile:           "(current)",
+	Dashboard:                "all servers",
+	ServerConnecting:         "connecting",
+	SessionPaused:            "session paused",
+	SessionNotListening:      "not accepting incoming connections",
+
+	SetupWelcome:         "Welcome to osprey! Let's connect to your Porla instance.",
+	SetupHint:            "The settings will be saved to %s.",
+	Endpoint:             "JSON-RPC endpoint",
+	SecretKey:            "Secret key",
+	SecretKeyHint:        "The token Porla uses to authenticate requests.",
+	SecretKeyPlaceHolder: "secret key",
+	SecretKeyRequired:    "the secret key is required",
+	TestingConnection:    "Testing the connection...",
+	SetupSaved:           "Connected to Porla %s, settings saved to %s.",
+
+	LoginTitle:          "Log in to %s",
+	LoginHint:           "Porla rejected the secret key, it may have expired. Log in to get a new one.",
+	Username:            "Username",
+	Password:            "Password",
+	UsernamePlaceHolder: "username",
+	PasswordPlaceHolder: "password",
+	WrongCredentials:    "wrong username or password",
+	LoggingIn:           "Logging in...",
 }
 
 var French = I18n{
@@ -151,12 +391,19 @@ var French = I18n{
 	SavePath:          "Chemin d'enregistrement",
 
 	DeletingTorrentName: "Suppression de %s",
+	DeletingTorrents:    "Suppression de %d torrents",
 	KeepDataQuestion:    "Garder les données?",
 
+	RecheckingTorrentName: "Revérification de %s",
+	RecheckingTorrents:    "Revérification de %d torrents",
+	RecheckQuestion:       "%s de données vont être relus sur le disque, ce qui peut prendre du temps. Revérifier?",
+
 	MovingTorrentName: "Déplacement de %s",
+	MovingTorrents:    "Déplacement de %d torrents",
 	NewSavePath:       "Nouveau chemin d'enregistrement",
 
 	TorrentSettingsForTorrentName: "Réglages du torrent %s",
+	TorrentSettingsForTorrents:    "Réglages de %d torrents",
 	AutomaticallyManaged:          "Géré automatiquement",
 	SequentialDownload:            "Téléchargement séquentiel",
 	DownloadLimit:                 "Limite de la vitesse de téléchargement",
@@ -167,6 +414,11 @@ var French = I18n{
 
 	TorrentsActive: "%s actif(s)",
 	Torrent:        "torrent",
+	TorrentsMarked: "%d marqué(s)",
+
+	FilterPlaceHolder: "nom, /regex/, state:, path:, ratio:, size:, is:error",
+	FilterHint:        "par ex. ubuntu state:diffusion,interrompu path:/data ratio:<1 size:1GB..10GB",
+	FilterActive:      "filtre : %s",
 
 	PageInfo: "Page %d/%d (max %d résultats)",
 
@@ -191,6 +443,28 @@ var French = I18n{
 		ToggleMagnetTorrentKeybind: "tab: basculer de lien magnet à fichier .torrent",
 		ToggleOptionKeybind:        "espace: faire basculer l'option",
 		NinjaModeKeybind:           "n : activer/désactiver le mode ninja",
+		DismissErrorKeybind:        "esc: ignorer l'erreur",
+		RetryNowKeybind:            "r: réessayer maintenant",
+		SwitchProfileKeybind:       "P: changer de profil",
+		DashboardKeybind:           "D: tous les serveurs",
+		TestAndSaveKeybind:         "enter: tester et enregistrer",
+		LogInKeybind:               "enter: se connecter",
+		QuitEscKeybind:             "esc: quitter",
+		MarkTorrentsKeybind:        "space/V/*: marquer un/plage/tous",
+		ClearMarksKeybind:          "esc: effacer les marques",
+		FilterKeybind:              "/: filtrer",
+		ApplyFilterKeybind:         "enter: appliquer le filtre",
+		ClearFilterKeybind:         "esc: effacer le filtre",
+		SortKeybind:                "o/O: trier/inverser",
+		DetailsKeybind:             "enter: détails",
+		ChangeTabKeybind:           "tab/shift+tab: changer d'onglet",
+		ToggleFolderKeybind:        "enter: ouvrir/fermer le dossier",
+		FilePriorityKeybind:        "+/-: augmenter/baisser la priorité",
+		AddPeerKeybind:             "a: ajouter un pair",
+		EditTrackersKeybind:        "a/e/x: ajouter/modifier/supprimer le tracker",
+		ReannounceScrapeKeybind:    "R/S: réannoncer/interroger",
+		RecheckReannounceKeybind:   "c/R: revérifier/réannoncer",
+		QueueKeybind:               "[/]/{/}: monter/descendre/début/fin de file",
 	},
 
 	TorrentStates: i18nTorrentStates{
@@ -207,9 +481,105 @@ var French = I18n{
 		Unknown:             "inconnu",
 	},
 
+	SortFields: i18nSortFields{
+		Name:          "nom",
+		Size:          "taille",
+		Progress:      "progression",
+		DownloadRate:  "vitesse de téléchargement",
+		UploadRate:    "vitesse d'envoi",
+		Peers:         "pairs",
+		Seeds:         "sources",
+		QueuePosition: "position dans la file",
+		ETA:           "temps restant",
+		State:         "état",
+	},
+	SortedBy: "trié par %s",
+
+	GeneralTab:       "Général",
+	FilesTab:         "Fichiers",
+	PeersTab:         "Pairs",
+	TrackersTab:      "Trackers",
+	InfoHashV1:       "Info hash v1",
+	InfoHashV2:       "Info hash v2",
+	Downloaded:       "Téléchargé",
+	DownloadedValue:  "%s sur %s",
+	QueuePosition:    "Position dans la file",
+	Ratio:            "Ratio",
+	AddedOn:          "Ajouté",
+	Unlimited:        "illimité",
+	Unknown:          "inconnu",
+	None:             "aucun",
+	NoFiles:          "Pas encore de fichiers, les métadonnées sont en cours de téléchargement.",
+	PrioritySkip:     "ignoré",
+	PriorityLow:      "basse",
+	PriorityNormal:   "normale",
+	PriorityHigh:     "haute",
+	PriorityMixed:    "mixte",
+	NoPeers:          "Aucun pair connecté.",
+	NoTrackers:       "Aucun tracker.",
+	PeerAddress:      "Adresse",
+	PeerClient:       "Client",
+	PeerProgress:     "Progression",
+	PeerDownloadRate: "Réception",
+	PeerUploadRate:   "Envoi",
+	PeerFlags:        "Drapeaux",
+	PeerFlagsLegend:  "I : entrant, E : chiffré, P : µTP, S : source, X : bridé",
+	AddPeer:          "Ajouter un pair :",
+	TrackerTier:      "Niveau",
+	TrackerStatus:    "État",
+	TrackerSeeds:     "Sources",
+	TrackerPeers:     "Pairs",
+	NextAnnounce:     "Prochaine annonce",
+	AddTracker:       "Ajouter un tracker :",
+	TrackerURL:       "URL du tracker :",
+	TrackerWorking:   "fonctionne",
+	TrackerUpdating:  "annonce en cours",
+	TrackerFails:     "%d échecs",
+
 	SeeYouLater:              "Au revoir!",
 	ErrorNonExistantView:     "Erreur: Vue non existante appelée.",
 	ConnectingToPorlaBackend: "Connection au backend de Porla.",
+	ErrorBanner:              "Erreur: %s",
+	LoadingTorrentsFromPorla: "Connecté à Porla %s, chargement des torrents.",
+	ConnectionFailed:         "Échec de la connexion: %s",
+	AuthenticationFailed:     "échec de l'authentification, vérifiez la clé secrète",
+	RetryingIn:               "Nouvel essai dans %s (tentative %d).",
+	SwitchProfile:            "Changer de profil",
+	CurrentProfile:           "(actuel)",
+	Dashboard:                "tous les serveurs",
+	ServerConnecting:         "connexion",
+	SessionPaused:            "session en pause",
+	SessionNotListening:      "n'accepte pas de connexions entrantes",
+
+	SetupWelcome:         "Bienvenue dans osprey! Connectons-nous à votre instance de Porla.",
+	SetupHint:            "Les réglages seront enregistrés dans %s.",
+	Endpoint:             "Point d'accès JSON-RPC",
+	SecretKey:            "Clé secrète",
+	SecretKeyHint:        "Le jeton utilisé par Porla pour authentifier les requêtes.",
+	SecretKeyPlaceHolder: "clé secrète",
+	SecretKeyRequired:    "la clé secrète est requise",
+	TestingConnection:    "Test de la connexion...",
+	SetupSaved:           "Connecté à Porla %s, réglages enregistrés dans %s.",
+
+	LoginTitle:          "Connexion à %s",
+	LoginHint:           "Porla a refusé la clé secrète, elle a peut-être expiré. Connectez-vous pour en obtenir une nouvelle.",
+	Username:            "Nom d'utilisateur",
+	Password:            "Mot de passe",
+	UsernamePlaceHolder: "nom d'utilisateur",
+	PasswordPlaceHolder: "mot de passe",
+	WrongCredentials:    "nom d'utilisateur ou mot de passe incorrect",
+	LoggingIn:           "Connexion...",
+}
+
+var Languages = []string{"English", "French"}
+
+func IsKnownLanguage(I18nLanguage string) bool {
+	for _, language := range Languages {
+		if language == I18nLanguage {
+			return true
+		}
+	}
+	return false
 }
 
 func LoadLanguage(I18nLanguage string) I18n {
@@ -0,0 +1,201 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+const Version = "2.0"
+
+type Request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *RequestError   `json:"error"`
+}
+
+type RequestError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("jsonrpc: %s (code %d)", e.Message, e.Code)
+}
+
+// StatusError is returned when the endpoint answers with a non 2xx HTTP status,
+// which Porla does for authentication failures before any JSON-RPC handling.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "jsonrpc: unexpected HTTP status " + e.Status
+}
+
+type Client struct {
+	Endpoint   string
+	Token      string
+	HTTPClient *http.Client
+
+	lastID uint64
+}
+
+func NewClient(endpoint, token string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		Endpoint:   endpoint,
+		Token:      token,
+		HTTPClient: httpClient,
+	}
+}
+
+func (c *Client) nextID() uint64 {
+	return atomic.AddUint64(&c.lastID, 1)
+}
+
+// Call invokes method with params and decodes the result into result, which
+// may be nil when the caller does not care about the response payload.
+func (c *Client) Call(ctx context.Context, method string, params, result any) error {
+	request := Request{
+		JSONRPC: Version,
+		ID:      c.nextID(),
+		Method:  method,
+		Params:  params,
+	}
+	var response Response
+	if err := c.post(ctx, request, &response); err != nil {
+		return err
+	}
+	return response.decode(result)
+}
+
+func (r Response) decode(result any) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	if result == nil || len(r.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Result, result)
+}
+
+var ErrBatchUnsupported = errors.New("jsonrpc: batch requests are not supported by the server")
+
+// Servers without batch support may reject the array before looking at it.
+// Authentication failures are not among these statuses, they must not turn
+// batching off.
+var batchUnsupportedStatus = map[int]bool{
+	http.StatusBadRequest:           true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusUnsupportedMediaType: true,
+	http.StatusNotImplemented:       true,
+}
+
+// BatchCall is one call of a batch request. Err is set by Batch to the error
+// of this call alone.
+type BatchCall struct {
+	Method string
+	Params any
+	Result any
+	Err    error
+}
+
+// Batch sends all calls in a single request and decodes each response into
+// the call with the same id, in whatever order the server answers. The
+// returned error is for the request as a whole.
+func (c *Client) Batch(ctx context.Context, calls []*BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	requests := make([]Request, len(calls))
+	pending := make(map[uint64]*BatchCall, len(calls))
+	for i, call := range calls {
+		requests[i] = Request{
+			JSONRPC: Version,
+			ID:      c.nextID(),
+			Method:  call.Method,
+			Params:  call.Params,
+		}
+		pending[requests[i].ID] = call
+		call.Err = nil
+	}
+	var body json.RawMessage
+	if err := c.post(ctx, requests, &body); err != nil {
+		var statusError *StatusError
+		if errors.As(err, &statusError) && batchUnsupportedStatus[statusError.StatusCode] {
+			return fmt.Errorf("%w: %v", ErrBatchUnsupported, err)
+		}
+		return err
+	}
+	// A server that can't handle the batch answers with a single response
+	if body = bytes.TrimSpace(body); len(body) == 0 || body[0] != '[' {
+		var response Response
+		if err := json.Unmarshal(body, &response); err == nil && response.Error != nil {
+			return fmt.Errorf("%w: %v", ErrBatchUnsupported, response.Error)
+		}
+		return ErrBatchUnsupported
+	}
+	var responses []Response
+	if err := json.Unmarshal(body, &responses); err != nil {
+		return err
+	}
+	for _, response := range responses {
+		call, ok := pending[response.ID]
+		if !ok {
+			continue
+		}
+		delete(pending, response.ID)
+		call.Err = response.decode(call.Result)
+	}
+	for _, call := range pending {
+		call.Err = fmt.Errorf("jsonrpc: no response to %s", call.Method)
+	}
+	return nil
+}
+
+func (c *Client) post(ctx context.Context, payload, response any) error {
+	requestBody, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return json.Unmarshal(body, response)
+}
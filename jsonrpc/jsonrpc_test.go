@@ -0,0 +1,169 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newServer answers every request with reply, given the decoded body.
+func newServer(t *testing.T, reply func(w http.ResponseWriter, body json.RawMessage)) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("got Authorization %q", got)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		reply(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, "token", server.Client())
+}
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		want     string
+		wantErr  func(error) bool
+	}{
+		{"result", http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"name":"porla"}}`, "porla", nil},
+		{"error object", http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`, "", func(err error) bool {
+			var requestError *RequestError
+			return errors.As(err, &requestError) && requestError.Code == -32601
+		}},
+		{"HTTP status", http.StatusUnauthorized, `unauthorized`, "", func(err error) bool {
+			var statusError *StatusError
+			return errors.As(err, &statusError) && statusError.StatusCode == http.StatusUnauthorized
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newServer(t, func(w http.ResponseWriter, body json.RawMessage) {
+				var request Request
+				if err := json.Unmarshal(body, &request); err != nil {
+					t.Error(err)
+				}
+				if request.JSONRPC != Version || request.ID != 1 || request.Method != "sys.versions" {
+					t.Errorf("unexpected request %s", body)
+				}
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.response)
+			})
+			var result struct{ Name string }
+			err := client.Call(context.Background(), "sys.versions", nil, &result)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !tt.wantErr(err) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.Name != tt.want {
+				t.Errorf("got %q, want %q", result.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		name string
+		// reply gets the ids of the requests in the order they were sent
+		reply   func(ids []uint64) (int, string)
+		wantErr error
+		// The expected result of each call, or "error" when it failed
+		want []string
+	}{
+		{"in order", func(ids []uint64) (int, string) {
+			return http.StatusOK, `[` + result(ids[0], "a") + `,` + result(ids[1], "b") + `,` + result(ids[2], "c") + `]`
+		}, nil, []string{"a", "b", "c"}},
+		{"out of order", func(ids []uint64) (int, string) {
+			return http.StatusOK, `[` + result(ids[2], "c") + `,` + result(ids[0], "a") + `,` + result(ids[1], "b") + `]`
+		}, nil, []string{"a", "b", "c"}},
+		{"missing id", func(ids []uint64) (int, string) {
+			return http.StatusOK, `[` + result(ids[0], "a") + `,` + result(ids[2], "c") + `]`
+		}, nil, []string{"a", "error", "c"}},
+		{"unknown id", func(ids []uint64) (int, string) {
+			return http.StatusOK, `[` + result(ids[0], "a") + `,` + result(ids[1], "b") + `,` + result(ids[2], "c") + `,` + result(999, "z") + `]`
+		}, nil, []string{"a", "b", "c"}},
+		{"error object", func(ids []uint64) (int, string) {
+			return http.StatusOK, `[` + result(ids[0], "a") + `,{"jsonrpc":"2.0","id":` + id(ids[1]) + `,"error":{"code":-32602,"message":"Invalid params"}},` + result(ids[2], "c") + `]`
+		}, nil, []string{"a", "error", "c"}},
+		{"single response", func(ids []uint64) (int, string) {
+			return http.StatusOK, `{"jsonrpc":"2.0","id":0,"error":{"code":-32600,"message":"Invalid Request"}}`
+		}, ErrBatchUnsupported, nil},
+		{"rejected array", func(ids []uint64) (int, string) {
+			return http.StatusBadRequest, ``
+		}, ErrBatchUnsupported, nil},
+		{"not implemented", func(ids []uint64) (int, string) {
+			return http.StatusNotImplemented, ``
+		}, ErrBatchUnsupported, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newServer(t, func(w http.ResponseWriter, body json.RawMessage) {
+				var requests []Request
+				if err := json.Unmarshal(body, &requests); err != nil {
+					t.Error(err)
+				}
+				var ids []uint64
+				for _, request := range requests {
+					ids = append(ids, request.ID)
+				}
+				status, response := tt.reply(ids)
+				w.WriteHeader(status)
+				io.WriteString(w, response)
+			})
+			results := make([]string, 3)
+			calls := []*BatchCall{
+				{Method: "first", Result: &results[0]},
+				{Method: "second", Result: &results[1]},
+				{Method: "third", Result: &results[2]},
+			}
+			err := client.Batch(context.Background(), calls)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for i, call := range calls {
+				got := results[i]
+				if call.Err != nil {
+					got = "error"
+				}
+				if got != tt.want[i] {
+					t.Errorf("call %s: got %q, want %q", call.Method, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBatchUnauthorized(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		client := newServer(t, func(w http.ResponseWriter, body json.RawMessage) {
+			w.WriteHeader(status)
+		})
+		err := client.Batch(context.Background(), []*BatchCall{{Method: "torrents.list"}})
+		var statusError *StatusError
+		if errors.Is(err, ErrBatchUnsupported) || !errors.As(err, &statusError) || statusError.StatusCode != status {
+			t.Errorf("%d: got error %v", status, err)
+		}
+	}
+}
+
+func id(id uint64) string {
+	data, _ := json.Marshal(id)
+	return string(data)
+}
+
+func result(requestID uint64, value string) string {
+	data, _ := json.Marshal(value)
+	return `{"jsonrpc":"2.0","id":` + id(requestID) + `,"result":` + string(data) + `}`
+}
@@ -1,26 +1,122 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"os"
+	"strings"
 
+	"osprey/cli"
 	"osprey/config"
 	"osprey/http"
-	"osprey/i18n"
 	"osprey/ui"
 	"osprey/utils"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"gopkg.in/yaml.v3"
 )
 
 func main() {
-	f, err := os.ReadFile(config.ConfigFilePath)
-	utils.CheckError(err)
-	err = yaml.Unmarshal(f, &config.Config)
+	configPath := flag.String("config", "", "path to the config file")
+	profile := flag.String("profile", "", "name of the server profile to connect to")
+	var flagOverrides config.Overrides
+	flag.StringVar(&flagOverrides.JSONRPCEndpointURL, "endpoint", "", "URL of the Porla JSON-RPC endpoint")
+	flag.StringVar(&flagOverrides.SecretKey, "token", "", "Porla auth token")
+	flag.IntVar(&flagOverrides.PageSize, "pagesize", 0, "number of torrents per page")
+	flag.StringVar(&flagOverrides.I18nLanguage, "language", "", "interface language (English or French)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: osprey [options] [command]")
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "\nConfig file locations, in order: "+strings.Join(config.ConfigFileCandidates(*configPath), ", "))
+		fmt.Fprintln(flag.CommandLine.Output(), "Run `osprey help` for the list of commands.")
+	}
+	flag.Parse()
+	config.ReadPassphrase = func() (string, error) {
+		return cli.ReadPassword("Passphrase: ")
+	}
+
+	envOverrides, err := config.EnvOverrides()
 	utils.CheckError(err)
-	config.Currenti18n = i18n.LoadLanguage(config.Config.I18nLanguage)
+	overrides := envOverrides.Merge(flagOverrides)
+	checkConfig("flags and environment", overrides.Validate())
+
+	args := flag.Args()
+	// Anything else than a flag is a command, a mistyped one gets the usage
+	interactive := len(args) == 0 || strings.HasPrefix(args[0], "-")
+	ctx := context.Background()
+	if !interactive && (!cli.IsCommand(args[0]) || cli.IsOfflineCommand(args[0])) {
+		os.Exit(cli.Run(ctx, args))
+	}
+	// osprey login is how a missing or expired secret key gets replaced
+	needsSecretKey := interactive || cli.NeedsSecretKey(args[0])
+
+	// Without a config file osprey can still run from the environment and
+	// flags, the TUI offers to create one otherwise.
+	path, err := config.FindConfigFile(*configPath)
+	switch {
+	case err == nil:
+		utils.CheckError(config.Load(path))
+	case config.IsNoConfigFile(err) && (interactive || overrides.JSONRPCEndpointURL != ""):
+		config.ConfigFilePath = config.NewConfigFilePath(*configPath)
+	default:
+		utils.CheckError(err)
+	}
+	if interactive && config.UsesEncryptedSecretKey() {
+		utils.CheckError(config.Unlock())
+	}
+	if err := useProfile(*profile, overrides); needsSecretKey {
+		checkConfig(config.ConfigFilePath, err)
+	}
+
+	if interactive && config.NeedsSetup() {
+		if !runSetup() {
+			return
+		}
+		utils.CheckError(config.Load(config.ConfigFilePath))
+		checkConfig(config.ConfigFilePath, useProfile(*profile, overrides))
+	}
+	checkConfig(config.ConfigFilePath, config.Validate())
+	if needsSecretKey {
+		checkConfig(config.ConfigFilePath, config.RequireSecretKey())
+	}
 	http.InitHTTPClient()
+
+	if !interactive {
+		os.Exit(cli.Run(ctx, args))
+	}
+	// The TUI owns the terminal from now on, switching to a profile with an
+	// encrypted secret key relies on the passphrase given at startup.
+	config.ReadPassphrase = nil
 	p := tea.NewProgram(ui.InitialModel())
 	_, err = p.Run()
 	utils.CheckError(err)
 }
+
+// useProfile returns the error reading the secret key of the profile, which
+// only matters to commands that need it.
+func useProfile(name string, overrides config.Overrides) error {
+	utils.CheckError(config.UseProfile(name))
+	config.ApplyOverrides(overrides)
+	profile, err := config.ResolveSecretKey(config.Current)
+	config.Current = profile
+	return err
+}
+
+func checkConfig(source string, err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "osprey: invalid config (%s):\n", source)
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Fprintln(os.Stderr, "  "+line)
+	}
+	os.Exit(1)
+}
+
+// runSetup starts the first run wizard and reports whether a working
+// connection was saved.
+func runSetup() bool {
+	m, err := tea.NewProgram(ui.InitialSetupModel()).Run()
+	utils.CheckError(err)
+	return m.(ui.SetupModel).Done
+}
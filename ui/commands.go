@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"osprey/config"
+	"osprey/data/sessions"
+	"osprey/data/sys"
+	"osprey/data/torrents"
+	"osprey/http"
+	"osprey/jsonrpc"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	initialReconnectDelay = time.Second
+	maxReconnectDelay     = 30 * time.Second
+)
+
+const (
+	KeepCursor = iota
+	CursorToTop
+	CursorToBottom
+)
+
+type (
+	torrentListLoadedMsg struct {
+		requestID   int
+		torrentList torrents.TorrentList
+		page        int
+		sessions    []sessions.Session
+		cursor      int
+		// The properties of selected, see detailsSelection
+		selected   torrents.InfoHash
+		properties *torrents.TorrentProperties
+		err        error
+	}
+	torrentPropertiesLoadedMsg struct {
+		target            TargetTorrent
+		torrentProperties torrents.TorrentProperties
+		err               error
+	}
+	actionResultMsg struct {
+		err error
+	}
+	versionsLoadedMsg struct {
+		attempt  int
+		versions sys.Versions
+		err      error
+	}
+	reconnectMsg struct {
+		attempt int
+	}
+	loginResultMsg struct {
+		token string
+		err   error
+	}
+)
+
+// Commands run concurrently with Update, which may replace http.Default or
+// change the page size, so they get what they need when they are created.
+func loadVersions(attempt int) tea.Cmd {
+	connection := http.Default
+	return func() tea.Msg {
+		versions, err := connection.GetVersions(context.Background())
+		return versionsLoadedMsg{
+			attempt:  attempt,
+			versions: versions,
+			err:      err,
+		}
+	}
+}
+
+func reconnectAfter(attempt int, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return reconnectMsg{attempt: attempt}
+	})
+}
+
+func reconnectDelay(retries int) time.Duration {
+	delay := initialReconnectDelay
+	for i := 0; i < retries && delay < maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxReconnectDelay {
+		return maxReconnectDelay
+	}
+	return delay
+}
+
+// Porla answering with a JSON-RPC error means the connection itself is fine.
+func isConnectionError(err error) bool {
+	var requestError *jsonrpc.RequestError
+	return err != nil && !errors.As(err, &requestError)
+}
+
+func loadTorrentList(requestID, page, cursor int, options http.ListOptions, selected *torrents.Torrent) tea.Cmd {
+	connection, pageSize := http.Default, config.Current.PageSize
+	return func() tea.Msg {
+		snapshot, err := connection.GetSnapshot(context.Background(), page, pageSize, options, selected)
+		msg := torrentListLoadedMsg{
+			requestID:   requestID,
+			torrentList: snapshot.TorrentList,
+			page:        snapshot.Page,
+			sessions:    snapshot.Sessions,
+			cursor:      cursor,
+			properties:  snapshot.Properties,
+			err:         err,
+		}
+		if selected != nil {
+			msg.selected = selected.InfoHash
+		}
+		return msg
+	}
+}
+
+func loadTorrentProperties(target TargetTorrent) tea.Cmd {
+	return func() tea.Msg {
+		torrentProperties, err := target.Connection.GetTorrentProperties(context.Background(), target.Torrent)
+		return torrentPropertiesLoadedMsg{
+			target:            target,
+			torrentProperties: torrentProperties,
+			err:               err,
+		}
+	}
+}
+
+func runAction(action func(context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		return actionResultMsg{err: action(context.Background())}
+	}
+}
+
+// Ticks don't stack refreshes: a new one is only sent when none is in flight.
+// Explicit page changes always send a new request, and responses to older
+// requests are dropped when they arrive.
+func requestTorrentList(m *Model, page, cursor int) tea.Cmd {
+	m.ListRequestID++
+	m.ListRequestInFlight = true
+	return loadTorrentList(m.ListRequestID, page, cursor, http.ListOptions{
+		Filter: m.FilterState.Filter,
+		Sort:   config.Config.Sort,
+	}, detailsSelection(*m))
+}
+
+func refreshTorrentList(m *Model) tea.Cmd {
+	if m.ListRequestInFlight {
+		return nil
+	}
+	return requestTorrentList(m, m.Page, KeepCursor)
+}
+
+type dashboardServerLoadedMsg struct {
+	generation int
+	server     int
+	torrents   []torrents.Torrent
+	err        error
+}
+
+func loadDashboardServer(generation, server int, connection *http.Connection) tea.Cmd {
+	return func() tea.Msg {
+		all, err := connection.GetAllTorrents(context.Background())
+		return dashboardServerLoadedMsg{
+			generation: generation,
+			server:     server,
+			torrents:   all,
+			err:        err,
+		}
+	}
+}
+
+func login(connection *http.Connection, username, password string) tea.Cmd {
+	return func() tea.Msg {
+		token, err := connection.Login(context.Background(), username, password)
+		return loginResultMsg{token: token, err: err}
+	}
+}
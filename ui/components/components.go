@@ -8,7 +8,6 @@ import (
 	"osprey/ninja"
 	"osprey/ui/styling"
 	"strings"
-	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/muesli/termenv"
@@ -44,22 +43,32 @@ func Progressbar(width int, percent float64) string {
 	return fmt.Sprintf("%s%s %3.0f", fullCells, emptyCells, math.Round(percent*100))
 }
 
-func Torrent(torrent torrents.Torrent, index int, obfuscate, selected bool) string {
+// Torrent renders a torrent of the list, selected is the one under the cursor
+// and marked ones are part of the selection bulk actions apply to.
+func Torrent(torrent torrents.Torrent, index int, obfuscate, selected, marked bool) string {
 	s := ""
 	torrentName := torrent.Name
 	if obfuscate {
 		torrentName = ninja.RandomLinuxTorrent(index)
 	}
-	torrentNameString := fmt.Sprintf("- %-9s %s\n", fmt.Sprintf("[%s]", torrents.StateString(torrent)), torrentName)
+	bullet := "-"
+	if marked {
+		bullet = "*"
+	}
+	torrentNameString := fmt.Sprintf("%s %-9s %s\n", bullet, fmt.Sprintf("[%s]", torrents.StateString(torrent)), torrentName)
 	if selected {
 		s += styling.ColorFg(torrentNameString, styling.HighlightedColor)
 	} else {
 		s += styling.ColorFg(torrentNameString, torrents.StateColor(torrent))
 	}
 	torrentStatus := fmt.Sprintf("↓ %-9s  ↑ %-9s  ↔ %-9s  P %-6d  S %-6d", humanize.Bytes(torrent.DownloadRate)+"/s", humanize.Bytes(torrent.UploadRate)+"/s", humanize.Bytes(torrent.Size), torrent.NumPeers, torrent.NumSeeds)
+	// Torrents out of the download queue have a position of -1
+	if torrent.QueuePosition >= 0 {
+		torrentStatus += fmt.Sprintf("  Q %-4d", torrent.QueuePosition)
+	}
 	if torrent.State == 3 {
-		if torrent.DownloadRate != 0 {
-			torrentStatus += fmt.Sprintf("  E %-6s", time.Duration(((1.0 - torrent.Progress) * float64(torrent.Size) * 1000000000.0 / float64(torrent.DownloadRate))).Round(time.Second))
+		if eta, ok := torrents.ETA(torrent); ok {
+			torrentStatus += fmt.Sprintf("  E %-6s", eta)
 		} else {
 			torrentStatus += fmt.Sprintf("  E %-6s", "∞")
 		}
@@ -71,6 +80,20 @@ func Torrent(torrent torrents.Torrent, index int, obfuscate, selected bool) stri
 	return s
 }
 
+// DashboardTorrent renders a torrent on a single line, prefixed with the name
+// of the server it is on.
+func DashboardTorrent(serverName string, torrent torrents.Torrent, index int, obfuscate, selected bool) string {
+	torrentName := torrent.Name
+	if obfuscate {
+		torrentName = ninja.RandomLinuxTorrent(index)
+	}
+	s := fmt.Sprintf("%-12s %-14s %5.1f%%  ↓ %-9s  ↑ %-9s  %s", serverName, torrents.StateString(torrent), torrent.Progress*100, humanize.Bytes(torrent.DownloadRate)+"/s", humanize.Bytes(torrent.UploadRate)+"/s", torrentName)
+	if selected {
+		return styling.ColorFg(s, styling.HighlightedColor) + "\n"
+	}
+	return styling.ColorFg(s, torrents.StateColor(torrent)) + "\n"
+}
+
 func KeybindsHints(keybinds []string) string {
 	s := ""
 	for index, keybind := range keybinds {
@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/http"
+	"osprey/ui/components"
+	"osprey/ui/styling"
+
+	tea "github.com/charmbracelet/bubbletea"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/dustin/go-humanize/english"
+)
+
+type DashboardServer struct {
+	Connection *http.Connection
+	Torrents   []torrents.Torrent
+	Error      error
+	Loaded     bool
+	InFlight   bool
+	// Set when the secret key of the profile couldn't be read, the server is
+	// then never polled.
+	ConfigError error
+}
+
+type DashboardState struct {
+	Generation int
+	Servers    []DashboardServer
+	Cursor     int
+	// By profile name, kept across openings of the dashboard
+	Connections map[string]*http.Connection
+}
+
+type dashboardRow struct {
+	server  int
+	torrent torrents.Torrent
+}
+
+// Every opening of the dashboard loads the servers again, responses from a
+// previous generation are dropped. Connections are reused unless the profile
+// changed since, the ones left unused are closed.
+func openDashboard(m *Model) tea.Cmd {
+	m.DashboardState.Generation++
+	m.DashboardState.Servers = nil
+	m.DashboardState.Cursor = 0
+	connections := map[string]*http.Connection{}
+	for _, name := range config.ProfileNames() {
+		profile, err := config.Profile(name)
+		if err != nil {
+			continue
+		}
+		profile, err = config.ResolveSecretKey(profile)
+		connection := m.DashboardState.Connections[name]
+		if connection == nil || !reflect.DeepEqual(connection.Profile, profile) {
+			connection = http.Connect(profile)
+		}
+		connections[name] = connection
+		m.DashboardState.Servers = append(m.DashboardState.Servers, DashboardServer{
+			Connection:  connection,
+			Error:       err,
+			Loaded:      err != nil,
+			ConfigError: err,
+		})
+	}
+	for name, connection := range m.DashboardState.Connections {
+		if connections[name] != connection {
+			connection.Close()
+		}
+	}
+	m.DashboardState.Connections = connections
+	return pollDashboard(m)
+}
+
+// Servers are polled concurrently, a slow one doesn't hold back the others
+// and is skipped until its previous request returns.
+func pollDashboard(m *Model) tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range m.DashboardState.Servers {
+		server := &m.DashboardState.Servers[i]
+		if server.InFlight || server.ConfigError != nil {
+			continue
+		}
+		server.InFlight = true
+		cmds = append(cmds, loadDashboardServer(m.DashboardState.Generation, i, server.Connection))
+	}
+	return tea.Batch(cmds...)
+}
+
+func updateDashboardServerLoaded(msg dashboardServerLoadedMsg, m Model) (tea.Model, tea.Cmd) {
+	if msg.generation != m.DashboardState.Generation {
+		return m, nil
+	}
+	server := &m.DashboardState.Servers[msg.server]
+	server.InFlight = false
+	server.Error = msg.err
+	if msg.err == nil {
+		server.Torrents = msg.torrents
+		server.Loaded = true
+		if m.CurrentView == DetailsIota && m.Target.Connection == server.Connection {
+			refreshDetailsTorrent(&m, server.Torrents)
+		}
+	}
+	rows := dashboardRows(m.DashboardState)
+	if m.DashboardState.Cursor > len(rows)-1 {
+		m.DashboardState.Cursor = len(rows) - 1
+	}
+	if m.DashboardState.Cursor < 0 {
+		m.DashboardState.Cursor = 0
+	}
+	return m, nil
+}
+
+// The torrents of all servers merged and ordered by name
+func dashboardRows(d DashboardState) []dashboardRow {
+	var rows []dashboardRow
+	for i, server := range d.Servers {
+		for _, torrent := range server.Torrents {
+			rows = append(rows, dashboardRow{server: i, torrent: torrent})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return strings.ToLower(rows[i].torrent.Name) < strings.ToLower(rows[j].torrent.Name)
+	})
+	return rows
+}
+
+func dashboardRowsPerPage(d DashboardState) int {
+	// Torrents in the list view take 4 lines, here they take one
+	rowsPerPage := config.Current.PageSize*4 - len(d.Servers)
+	if rowsPerPage < 1 {
+		return 1
+	}
+	return rowsPerPage
+}
+
+func dashboardTarget(m Model) (TargetTorrent, bool) {
+	rows := dashboardRows(m.DashboardState)
+	if len(rows) == 0 {
+		return TargetTorrent{}, false
+	}
+	row := rows[m.DashboardState.Cursor]
+	return TargetTorrent{
+		Connection: m.DashboardState.Servers[row.server].Connection,
+		Torrent:    row.torrent,
+	}, true
+}
+
+func updateDashboardView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
+	rowCount := len(dashboardRows(m.DashboardState))
+	rowsPerPage := dashboardRowsPerPage(m.DashboardState)
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "D":
+			m.CurrentView = TorrentListIota
+			return m, refreshTorrentList(&m)
+		case "up", "k":
+			if m.DashboardState.Cursor > 0 {
+				m.DashboardState.Cursor--
+			}
+		case "down", "j":
+			if m.DashboardState.Cursor < rowCount-1 {
+				m.DashboardState.Cursor++
+			}
+		case "left", "g":
+			m.DashboardState.Cursor -= rowsPerPage
+			if m.DashboardState.Cursor < 0 {
+				m.DashboardState.Cursor = 0
+			}
+		case "right", "h":
+			m.DashboardState.Cursor += rowsPerPage
+			if m.DashboardState.Cursor > rowCount-1 {
+				m.DashboardState.Cursor = rowCount - 1
+			}
+		case "enter":
+			if target, ok := dashboardTarget(m); ok {
+				return m, openDetails(&m, target)
+			}
+		case "p":
+			if target, ok := dashboardTarget(m); ok {
+				return m, runAction(func(ctx context.Context) error {
+					return target.Connection.PauseResumeTorrent(ctx, target.Torrent)
+				})
+			}
+		case "r":
+			if target, ok := dashboardTarget(m); ok {
+				m.Target = target
+				openSubMenu(&m, RemoveTorrentIota)
+			}
+		case "m":
+			if target, ok := dashboardTarget(m); ok {
+				m.Target = target
+				m.MoveTorrentSubMenuState.MoveTorrentPathTextInput.SetValue(target.Torrent.SavePath)
+				openSubMenu(&m, MoveTorrentIota)
+			}
+		case "n":
+			m.NinjaMode = !m.NinjaMode
+		}
+	case tickMsg:
+		return m, tea.Batch(tick(), pollDashboard(&m))
+	}
+	return m, nil
+}
+
+func dashboardView(m Model) string {
+	tpl := components.VersionNumber() + styling.Dot + styling.Subtle(config.Currenti18n.Dashboard) + "\n\n"
+	for _, server := range m.DashboardState.Servers {
+		tpl += dashboardServerSummary(server) + "\n"
+	}
+	tpl += "\n"
+
+	rows := dashboardRows(m.DashboardState)
+	rowsPerPage := dashboardRowsPerPage(m.DashboardState)
+	start := m.DashboardState.Cursor / rowsPerPage * rowsPerPage
+	for i := start; i < len(rows) && i < start+rowsPerPage; i++ {
+		serverName := m.DashboardState.Servers[rows[i].server].Connection.Profile.Name
+		tpl += components.DashboardTorrent(serverName, rows[i].torrent, i, m.NinjaMode, i == m.DashboardState.Cursor)
+	}
+	tpl += "\n" + styling.Subtle(fmt.Sprintf(config.Currenti18n.PageInfo, start/rowsPerPage+1, (len(rows)-1)/rowsPerPage+1, rowsPerPage)) + "\n\n"
+	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.SelectKeybind, config.Currenti18n.Keybinds.ChangePageKeybind, config.Currenti18n.Keybinds.DetailsKeybind, config.Currenti18n.Keybinds.PauseResumeKeybind, config.Currenti18n.Keybinds.RemoveTorrentKeybind}) + "\n"
+	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.MoveTorrentKeybind, config.Currenti18n.Keybinds.NinjaModeKeybind, config.Currenti18n.Keybinds.EscKeybind, config.Currenti18n.Keybinds.QKeybind})
+	return tpl
+}
+
+func dashboardServerSummary(server DashboardServer) string {
+	var downloadRate, uploadRate uint64
+	for _, torrent := range server.Torrents {
+		downloadRate += torrent.DownloadRate
+		uploadRate += torrent.UploadRate
+	}
+	status := styling.ColorFg("●", styling.OnlineColor)
+	health := ""
+	switch {
+	case server.Error != nil:
+		status = styling.ColorFg("●", styling.ErrorColor)
+		health = styling.ColorFg(connectionErrorReason(server.Error), styling.ErrorColor)
+	case !server.Loaded:
+		status = styling.Subtle("●")
+		health = styling.Subtle(config.Currenti18n.ServerConnecting)
+	}
+	summary := fmt.Sprintf("%s %-12s ↓ %-9s  ↑ %-9s  %-14s", status, server.Connection.Profile.Name, humanize.Bytes(downloadRate)+"/s", humanize.Bytes(uploadRate)+"/s", english.Plural(len(server.Torrents), config.Currenti18n.Torrent, ""))
+	return summary + " " + health
+}
@@ -0,0 +1,425 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/http"
+	"osprey/ninja"
+	"osprey/ui/components"
+	"osprey/ui/styling"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	DetailsGeneralTab = iota
+	DetailsFilesTab
+	DetailsPeersTab
+	DetailsTrackersTab
+	detailsTabCount
+)
+
+// What the text input of the detail view is open for
+const (
+	detailsNoInput = iota
+	detailsAddPeerInput
+	detailsAddTrackerInput
+	detailsEditTrackerInput
+)
+
+// DetailsState is the detail view of m.Target, opened with enter from the
+// torrent list or the dashboard.
+type DetailsState struct {
+	Tab     int
+	Cursor  int
+	Details http.Details
+	// Whether Details holds the lists of the current tab
+	Loaded    bool
+	RequestID int
+	InFlight  bool
+	// The files tab shows Details.Files as a tree, Expanded holds the
+	// paths of the open folders
+	FileTree *torrents.FileNode
+	Expanded map[string]bool
+	// Not persisted, unlike the order of the torrent list
+	PeerSort config.SortType
+	// InputFor is detailsNoInput unless Input is being typed in
+	Input      textinput.Model
+	InputFor   int
+	InputError error
+	// The URL of the tracker the input edits
+	EditedTracker string
+}
+
+type detailsLoadedMsg struct {
+	requestID int
+	details   http.Details
+	err       error
+}
+
+func loadDetails(requestID int, target TargetTorrent, tab int) tea.Cmd {
+	parts := http.DetailsParts{
+		Properties: target.Connection != http.Default,
+		Files:      tab == DetailsFilesTab,
+		Peers:      tab == DetailsPeersTab,
+		Trackers:   tab == DetailsTrackersTab,
+	}
+	return func() tea.Msg {
+		details, err := target.Connection.GetTorrentDetails(context.Background(), target.Torrent, parts)
+		return detailsLoadedMsg{requestID: requestID, details: details, err: err}
+	}
+}
+
+// detailsAction runs action on the torrent of the detail view and reloads the
+// current tab once it is done.
+func detailsAction(m *Model, action func(context.Context, *http.Connection, torrents.Torrent) error) tea.Cmd {
+	m.DetailsState.RequestID++
+	m.DetailsState.InFlight = true
+	load := loadDetails(m.DetailsState.RequestID, m.Target, m.DetailsState.Tab)
+	requestID, target := m.DetailsState.RequestID, m.Target
+	return func() tea.Msg {
+		if err := action(context.Background(), target.Connection, target.Torrent); err != nil {
+			return detailsLoadedMsg{requestID: requestID, err: err}
+		}
+		return load()
+	}
+}
+
+func openDetails(m *Model, target TargetTorrent) tea.Cmd {
+	m.ReturnView = m.CurrentView
+	m.CurrentView = DetailsIota
+	m.Target = target
+	m.DetailsState = DetailsState{RequestID: m.DetailsState.RequestID}
+	if target.Connection == http.Default {
+		return tea.Batch(requestDetails(m), requestTorrentList(m, m.Page, KeepCursor))
+	}
+	return requestDetails(m)
+}
+
+func closeDetails(m *Model) tea.Cmd {
+	m.CurrentView = m.ReturnView
+	if m.CurrentView == DashboardIota {
+		return pollDashboard(m)
+	}
+	return refreshTorrentList(m)
+}
+
+func setDetailsTab(m *Model, tab int) tea.Cmd {
+	m.DetailsState.Tab = (tab + detailsTabCount) % detailsTabCount
+	m.DetailsState.Cursor = 0
+	m.DetailsState.Loaded = false
+	return requestDetails(m)
+}
+
+// Like the torrent list, responses to older requests are dropped and ticks
+// don't stack refreshes.
+func requestDetails(m *Model) tea.Cmd {
+	m.DetailsState.RequestID++
+	m.DetailsState.InFlight = true
+	return loadDetails(m.DetailsState.RequestID, m.Target, m.DetailsState.Tab)
+}
+
+func refreshDetails(m *Model) tea.Cmd {
+	if m.DetailsState.InFlight {
+		return nil
+	}
+	return requestDetails(m)
+}
+
+// detailsSelection is the torrent whose properties the torrent list snapshot
+// loads, nil unless the detail view shows a torrent of the current server.
+func detailsSelection(m Model) *torrents.Torrent {
+	if m.CurrentView != DetailsIota || m.Target.Connection != http.Default {
+		return nil
+	}
+	torrent := m.Target.Torrent
+	return &torrent
+}
+
+// The torrent itself is kept up to date by refreshing the list the detail
+// view was opened from.
+func refreshDetailsTorrent(m *Model, list []torrents.Torrent) {
+	for _, torrent := range list {
+		if torrent.InfoHash == m.Target.Torrent.InfoHash {
+			m.Target.Torrent = torrent
+		}
+	}
+}
+
+func detailsRowCount(m Model) int {
+	switch m.DetailsState.Tab {
+	case DetailsFilesTab:
+		return len(fileRows(m))
+	case DetailsPeersTab:
+		return len(m.DetailsState.Details.Peers)
+	case DetailsTrackersTab:
+		return len(m.DetailsState.Details.Trackers)
+	}
+	return 0
+}
+
+func detailsRowsPerPage() int {
+	// The list view has room for PageSize torrents of 4 lines, the tabs
+	// and the table header take 3 of them
+	if rows := config.Current.PageSize*4 - 3; rows > 0 {
+		return rows
+	}
+	return 1
+}
+
+func updateDetailsView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && m.DetailsState.InputFor != detailsNoInput {
+		return updateDetailsInput(msg, m)
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if m.Error != nil {
+				m.Error = nil
+				break
+			}
+			return m, closeDetails(&m)
+		case "tab":
+			return m, setDetailsTab(&m, m.DetailsState.Tab+1)
+		case "shift+tab":
+			return m, setDetailsTab(&m, m.DetailsState.Tab-1)
+		case "1", "2", "3", "4":
+			tab, _ := strconv.Atoi(msg.String())
+			return m, setDetailsTab(&m, tab-1)
+		case "up", "k":
+			if m.DetailsState.Cursor > 0 {
+				m.DetailsState.Cursor--
+			}
+		case "down", "j":
+			if m.DetailsState.Cursor < detailsRowCount(m)-1 {
+				m.DetailsState.Cursor++
+			}
+		case "n":
+			m.NinjaMode = !m.NinjaMode
+		default:
+			switch m.DetailsState.Tab {
+			case DetailsFilesTab:
+				return updateFilesTab(msg, m)
+			case DetailsPeersTab:
+				return updatePeersTab(msg, m)
+			case DetailsTrackersTab:
+				return updateTrackersTab(msg, m)
+			}
+		}
+	case detailsLoadedMsg:
+		if msg.requestID != m.DetailsState.RequestID {
+			break
+		}
+		m.DetailsState.InFlight = false
+		if msg.err != nil {
+			m.Error = msg.err
+			break
+		}
+		if msg.details.Properties == nil {
+			msg.details.Properties = m.DetailsState.Details.Properties
+		}
+		m.DetailsState.Details = msg.details
+		m.DetailsState.Loaded = true
+		if m.DetailsState.Tab == DetailsFilesTab {
+			updateFileTree(&m)
+		}
+		torrents.SortPeers(m.DetailsState.Details.Peers, m.DetailsState.PeerSort)
+		if m.DetailsState.Cursor > detailsRowCount(m)-1 {
+			m.DetailsState.Cursor = detailsRowCount(m) - 1
+		}
+		if m.DetailsState.Cursor < 0 {
+			m.DetailsState.Cursor = 0
+		}
+	case tickMsg:
+		refreshList := refreshTorrentList(&m)
+		if m.ReturnView == DashboardIota {
+			refreshList = pollDashboard(&m)
+		}
+		return m, tea.Batch(tick(), refreshDetails(&m), refreshList)
+	}
+	return m, nil
+}
+
+func detailsView(m Model) string {
+	torrent := m.Target.Torrent
+	name := torrent.Name
+	if m.NinjaMode {
+		name = ninja.RandomLinuxTorrent(int(torrent.QueuePosition))
+	}
+	tpl := components.VersionNumber() + styling.Dot + styling.Subtle(m.Target.Connection.Profile.Name) + "\n\n"
+	tpl += styling.ColorFg(fmt.Sprintf("[%s] %s", torrents.StateString(torrent), name), torrents.StateColor(torrent)) + "\n"
+	tpl += components.Progressbar(20, torrent.Progress) + "\n\n"
+
+	tabs := []string{config.Currenti18n.GeneralTab, config.Currenti18n.FilesTab, config.Currenti18n.PeersTab, config.Currenti18n.TrackersTab}
+	for i, tab := range tabs {
+		if i != 0 {
+			tpl += "   "
+		}
+		if i == m.DetailsState.Tab {
+			tpl += styling.ColorFg("["+tab+"]", styling.HighlightedColor)
+		} else {
+			tpl += styling.Subtle(" " + tab + " ")
+		}
+	}
+	tpl += "\n\n"
+
+	switch m.DetailsState.Tab {
+	case DetailsGeneralTab:
+		tpl += detailsGeneral(m)
+	case DetailsFilesTab:
+		tpl += detailsFiles(m)
+	case DetailsPeersTab:
+		tpl += detailsPeers(m)
+	case DetailsTrackersTab:
+		tpl += detailsTrackers(m)
+	}
+
+	if m.DetailsState.InputFor != detailsNoInput {
+		return tpl + "\n" + detailsInputBar(m)
+	}
+	keybinds := []string{config.Currenti18n.Keybinds.ChangeTabKeybind, config.Currenti18n.Keybinds.SelectKeybind}
+	switch m.DetailsState.Tab {
+	case DetailsFilesTab:
+		keybinds = append(keybinds, config.Currenti18n.Keybinds.ToggleFolderKeybind, config.Currenti18n.Keybinds.FilePriorityKeybind)
+	case DetailsPeersTab:
+		keybinds = append(keybinds, config.Currenti18n.Keybinds.SortKeybind, config.Currenti18n.Keybinds.AddPeerKeybind)
+	case DetailsTrackersTab:
+		keybinds = append(keybinds, config.Currenti18n.Keybinds.EditTrackersKeybind, config.Currenti18n.Keybinds.ReannounceScrapeKeybind)
+	}
+	keybinds = append(keybinds, config.Currenti18n.Keybinds.NinjaModeKeybind, config.Currenti18n.Keybinds.EscKeybind, config.Currenti18n.Keybinds.QKeybind)
+	tpl += "\n" + components.KeybindsHints(keybinds)
+	return tpl
+}
+
+func openDetailsInput(m *Model, inputFor int, prompt, placeholder string) tea.Cmd {
+	input := textinput.New()
+	input.CharLimit = -1
+	input.Width = 50
+	input.Prompt = prompt
+	input.Placeholder = placeholder
+	m.DetailsState.Input = input
+	m.DetailsState.InputFor = inputFor
+	m.DetailsState.InputError = nil
+	return m.DetailsState.Input.Focus()
+}
+
+func closeDetailsInput(m *Model) {
+	m.DetailsState.InputFor = detailsNoInput
+	m.DetailsState.InputError = nil
+	m.DetailsState.Input.Blur()
+}
+
+func updateDetailsInput(msg tea.KeyMsg, m Model) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		var cmd tea.Cmd
+		var err error
+		switch m.DetailsState.InputFor {
+		case detailsAddPeerInput:
+			cmd, err = addPeer(&m, m.DetailsState.Input.Value())
+		case detailsAddTrackerInput:
+			cmd, err = addTracker(&m, m.DetailsState.Input.Value())
+		case detailsEditTrackerInput:
+			cmd, err = editTracker(&m, m.DetailsState.EditedTracker, m.DetailsState.Input.Value())
+		}
+		if err != nil {
+			m.DetailsState.InputError = err
+			return m, nil
+		}
+		closeDetailsInput(&m)
+		return m, cmd
+	case "esc":
+		closeDetailsInput(&m)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.DetailsState.Input, cmd = m.DetailsState.Input.Update(msg)
+	return m, cmd
+}
+
+func detailsInputBar(m Model) string {
+	tpl := m.DetailsState.Input.View() + "\n"
+	if m.DetailsState.InputError != nil {
+		tpl += styling.ColorFg(m.DetailsState.InputError.Error(), styling.ErrorColor) + "\n"
+	}
+	return tpl + components.KeybindsHints([]string{config.Currenti18n.Keybinds.DoneKeybind, config.Currenti18n.Keybinds.EscKeybind})
+}
+
+func detailsField(label, value string) string {
+	return styling.Subtle(fmt.Sprintf("%-22s", label)) + " " + value + "\n"
+}
+
+func detailsGeneral(m Model) string {
+	torrent := m.Target.Torrent
+	or := func(s, missing string) string {
+		if s == "" {
+			return styling.Subtle(missing)
+		}
+		return s
+	}
+	added := ""
+	if torrent.AddedOn > 0 {
+		addedOn := time.Unix(torrent.AddedOn, 0)
+		added = addedOn.Format("2006-01-02 15:04") + " (" + humanize.Time(addedOn) + ")"
+	}
+	tpl := detailsField(config.Currenti18n.InfoHashV1, or(torrent.InfoHash[0], config.Currenti18n.None))
+	tpl += detailsField(config.Currenti18n.InfoHashV2, or(torrent.InfoHash[1], config.Currenti18n.None))
+	tpl += detailsField(config.Currenti18n.SavePath, torrent.SavePath)
+	tpl += detailsField(config.Currenti18n.Downloaded, fmt.Sprintf(config.Currenti18n.DownloadedValue, humanize.Bytes(torrent.TotalDone), humanize.Bytes(torrent.Total)))
+	tpl += detailsField(config.Currenti18n.QueuePosition, strconv.FormatInt(torrent.QueuePosition, 10))
+	tpl += detailsField(config.Currenti18n.Ratio, strconv.FormatFloat(torrent.Ratio, 'f', 2, 64))
+	tpl += detailsField(config.Currenti18n.AddedOn, or(added, config.Currenti18n.Unknown))
+	properties := m.DetailsState.Details.Properties
+	if properties == nil {
+		return tpl
+	}
+	tpl += detailsField(config.Currenti18n.DownloadLimit, rateLimit(properties.DownloadLimit))
+	tpl += detailsField(config.Currenti18n.UploadLimit, rateLimit(properties.UploadLimit))
+	tpl += detailsField(config.Currenti18n.MaxConnections, strconv.Itoa(properties.MaxConnections))
+	tpl += detailsField(config.Currenti18n.MaxUploads, strconv.Itoa(properties.MaxUploads))
+	return tpl
+}
+
+// Porla sends -1, or 0 for torrents added without limits, when there is none.
+func rateLimit(limit int) string {
+	if limit <= 0 {
+		return config.Currenti18n.Unlimited
+	}
+	return humanize.Bytes(uint64(limit)) + "/s"
+}
+
+// detailsRows renders the page of rows around the cursor, or empty when the
+// list has nothing to show. Dimmed rows, if any, are shown in grey.
+func detailsRows(m Model, header string, rows []string, dimmed []bool, empty string) string {
+	if !m.DetailsState.Loaded {
+		return ""
+	}
+	if len(rows) == 0 {
+		return styling.Subtle(empty) + "\n"
+	}
+	tpl := ""
+	if header != "" {
+		tpl += styling.Subtle(header) + "\n"
+	}
+	rowsPerPage := detailsRowsPerPage()
+	start := m.DetailsState.Cursor / rowsPerPage * rowsPerPage
+	for i := start; i < len(rows) && i < start+rowsPerPage; i++ {
+		switch {
+		case i == m.DetailsState.Cursor:
+			tpl += styling.ColorFg(rows[i], styling.HighlightedColor) + "\n"
+		case i < len(dimmed) && dimmed[i]:
+			tpl += styling.Subtle(rows[i]) + "\n"
+		default:
+			tpl += rows[i] + "\n"
+		}
+	}
+	return tpl
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/http"
+	"osprey/ninja"
+
+	tea "github.com/charmbracelet/bubbletea"
+	humanize "github.com/dustin/go-humanize"
+)
+
+type fileRow struct {
+	node  *torrents.FileNode
+	depth int
+}
+
+// updateFileTree rebuilds the tree of the files tab after a refresh. Folders
+// at the top start expanded and the ones below them collapsed, so large
+// torrents open on a short list.
+func updateFileTree(m *Model) {
+	m.DetailsState.FileTree = torrents.FileTree(m.DetailsState.Details.Files)
+	if m.DetailsState.Expanded == nil {
+		m.DetailsState.Expanded = map[string]bool{}
+		for _, node := range m.DetailsState.FileTree.Children {
+			m.DetailsState.Expanded[node.Path] = node.IsFolder()
+		}
+	}
+}
+
+// fileRows lists the nodes shown in the files tab, leaving out the content of
+// collapsed folders.
+func fileRows(m Model) []fileRow {
+	var rows []fileRow
+	var walk func(node *torrents.FileNode, depth int)
+	walk = func(node *torrents.FileNode, depth int) {
+		for _, child := range node.Children {
+			rows = append(rows, fileRow{node: child, depth: depth})
+			if child.IsFolder() && m.DetailsState.Expanded[child.Path] {
+				walk(child, depth+1)
+			}
+		}
+	}
+	if m.DetailsState.FileTree != nil {
+		walk(m.DetailsState.FileTree, 0)
+	}
+	return rows
+}
+
+func toggleFolder(m *Model) {
+	rows := fileRows(*m)
+	if len(rows) == 0 {
+		return
+	}
+	if node := rows[m.DetailsState.Cursor].node; node.IsFolder() {
+		m.DetailsState.Expanded[node.Path] = !m.DetailsState.Expanded[node.Path]
+	}
+}
+
+// changeFilePriority moves the file or every file of the folder under the
+// cursor one priority level up or down, a folder with mixed priorities
+// starting from its lowest. The files are reloaded once Porla has set them.
+func changeFilePriority(m *Model, step int) tea.Cmd {
+	rows := fileRows(*m)
+	if len(rows) == 0 {
+		return nil
+	}
+	node := rows[m.DetailsState.Cursor].node
+	level, same := node.PriorityLevel()
+	newLevel := level + step
+	if newLevel < 0 || newLevel >= len(torrents.Priorities) {
+		if same {
+			return nil
+		}
+		newLevel = level
+	}
+	priority := torrents.Priorities[newLevel]
+	files := node.Files()
+	return detailsAction(m, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+		return connection.SetFilePriorities(ctx, torrent, files, priority)
+	})
+}
+
+func priorityName(level int, same bool) string {
+	if !same {
+		return config.Currenti18n.PriorityMixed
+	}
+	return []string{
+		config.Currenti18n.PrioritySkip,
+		config.Currenti18n.PriorityLow,
+		config.Currenti18n.PriorityNormal,
+		config.Currenti18n.PriorityHigh,
+	}[level]
+}
+
+func detailsFiles(m Model) string {
+	var rows []string
+	var skipped []bool
+	for i, row := range fileRows(m) {
+		node := row.node
+		name := node.Name
+		if m.NinjaMode {
+			name = ninja.RandomLinuxTorrent(i)
+		}
+		marker := "  "
+		if node.IsFolder() {
+			marker = "▸ "
+			if m.DetailsState.Expanded[node.Path] {
+				marker = "▾ "
+			}
+			name += "/"
+		}
+		progress := 1.0
+		if node.Size > 0 {
+			progress = float64(node.Progress) / float64(node.Size)
+		}
+		level, same := node.PriorityLevel()
+		rows = append(rows, fmt.Sprintf("%-8s %5.1f%%  %-9s  %s%s%s", priorityName(level, same), progress*100, humanize.Bytes(node.Size), strings.Repeat("  ", row.depth), marker, name))
+		skipped = append(skipped, same && torrents.Priorities[level] == torrents.PrioritySkip)
+	}
+	return detailsRows(m, "", rows, skipped, config.Currenti18n.NoFiles)
+}
+
+func updateFilesTab(msg tea.KeyMsg, m Model) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", " ":
+		toggleFolder(&m)
+	case "+", "=":
+		return m, changeFilePriority(&m, 1)
+	case "-":
+		return m, changeFilePriority(&m, -1)
+	}
+	return m, nil
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/ui/components"
+	"osprey/ui/styling"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FilterState is the search bar of the torrent list, opened with /.
+type FilterState struct {
+	Input   textinput.Model
+	Editing bool
+	// The filter of the list, only replaced when a query is submitted
+	Filter torrents.Filter
+	Error  error
+}
+
+func initialFilterState() FilterState {
+	input := textinput.New()
+	input.CharLimit = -1
+	input.Width = 50
+	input.Prompt = "/"
+	input.Placeholder = config.Currenti18n.FilterPlaceHolder
+	return FilterState{Input: input}
+}
+
+func openFilter(m *Model) tea.Cmd {
+	m.FilterState.Editing = true
+	m.FilterState.Input.CursorEnd()
+	return m.FilterState.Input.Focus()
+}
+
+// setFilter applies a filter to the list, starting over from its first page.
+func setFilter(m *Model, filter torrents.Filter) tea.Cmd {
+	m.FilterState.Filter = filter
+	m.FilterState.Input.SetValue(filter.Text)
+	m.Page = 0
+	return requestTorrentList(m, 0, CursorToTop)
+}
+
+func updateFilterBar(msg tea.KeyMsg, m Model) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		filter, err := torrents.ParseFilter(m.FilterState.Input.Value())
+		if err != nil {
+			m.FilterState.Error = err
+			return m, nil
+		}
+		m.FilterState.Editing = false
+		m.FilterState.Error = nil
+		m.FilterState.Input.Blur()
+		return m, setFilter(&m, filter)
+	case "esc":
+		m.FilterState.Editing = false
+		m.FilterState.Error = nil
+		m.FilterState.Input.Blur()
+		m.FilterState.Input.SetValue(m.FilterState.Filter.Text)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.FilterState.Input, cmd = m.FilterState.Input.Update(msg)
+	return m, cmd
+}
+
+// filterBar shows the query being typed, or the filter in effect.
+func filterBar(m Model) string {
+	if m.FilterState.Editing {
+		tpl := m.FilterState.Input.View() + "\n"
+		if m.FilterState.Error != nil {
+			tpl += styling.ColorFg(m.FilterState.Error.Error(), styling.ErrorColor) + "\n"
+		}
+		tpl += styling.Subtle(config.Currenti18n.FilterHint) + "\n"
+		return tpl + components.KeybindsHints([]string{config.Currenti18n.Keybinds.ApplyFilterKeybind, config.Currenti18n.Keybinds.EscKeybind}) + "\n\n"
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"osprey/config"
+	"osprey/http"
+	"osprey/ui/components"
+	"osprey/ui/styling"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	LoginUsernameInput = iota
+	LoginPasswordInput
+)
+
+type LoginState struct {
+	Inputs   []textinput.Model
+	InFlight bool
+	Error    error
+}
+
+func initialLoginState() LoginState {
+	var inputs []textinput.Model = make([]textinput.Model, 2)
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].CharLimit = -1
+		inputs[i].Width = 50
+		inputs[i].Prompt = ""
+	}
+	inputs[LoginUsernameInput].Placeholder = config.Currenti18n.UsernamePlaceHolder
+	inputs[LoginPasswordInput].Placeholder = config.Currenti18n.PasswordPlaceHolder
+	inputs[LoginPasswordInput].EchoMode = textinput.EchoPassword
+	return LoginState{Inputs: inputs}
+}
+
+// Porla rejected the secret key, ask for credentials instead of retrying with
+// it forever.
+func openLogin(m *Model) {
+	m.CurrentView = LoginIota
+	m.LoginState.InFlight = false
+	m.LoginState.Error = nil
+	m.LoginState.Inputs[LoginPasswordInput].Reset()
+	m.SubMenuCursor = LoginUsernameInput
+	if m.LoginState.Inputs[LoginUsernameInput].Value() != "" {
+		m.SubMenuCursor = LoginPasswordInput
+	}
+	m.SubMenuEntries = len(m.LoginState.Inputs)
+	for i := range m.LoginState.Inputs {
+		m.LoginState.Inputs[i].Blur()
+	}
+	m.LoginState.Inputs[m.SubMenuCursor].Focus()
+}
+
+func updateLoginView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd = make([]tea.Cmd, len(m.LoginState.Inputs))
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.LoginState.InFlight {
+			return m, nil
+		}
+		switch msg.String() {
+		case "enter":
+			if m.SubMenuCursor < len(m.LoginState.Inputs)-1 {
+				m.SubMenuCursor++
+				break
+			}
+			username := m.LoginState.Inputs[LoginUsernameInput].Value()
+			password := m.LoginState.Inputs[LoginPasswordInput].Value()
+			if username == "" || password == "" {
+				break
+			}
+			m.LoginState.InFlight = true
+			m.LoginState.Error = nil
+			return m, login(http.Default, username, password)
+		}
+
+		for i := range m.LoginState.Inputs {
+			m.LoginState.Inputs[i].Blur()
+		}
+		m.LoginState.Inputs[m.SubMenuCursor].Focus()
+
+	case loginResultMsg:
+		m.LoginState.InFlight = false
+		if http.IsUnauthorized(msg.err) {
+			m.LoginState.Error = errors.New(config.Currenti18n.WrongCredentials)
+			m.LoginState.Inputs[LoginPasswordInput].Reset()
+			return m, nil
+		}
+		if msg.err != nil {
+			m.LoginState.Error = msg.err
+			return m, nil
+		}
+		m.LoginState.Inputs[LoginPasswordInput].Reset()
+		// The new token is used for this session even when it can't be saved
+		if err := config.StoreSecretKey(config.Current.Name, msg.token); err != nil {
+			m.Error = err
+		}
+		config.Current.SecretKey = msg.token
+		http.InitHTTPClient()
+		return m, reconnect(&m)
+
+	case tickMsg:
+		return m, tick()
+	}
+
+	for i := range m.LoginState.Inputs {
+		m.LoginState.Inputs[i], cmds[i] = m.LoginState.Inputs[i].Update(msg)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func loginView(m Model) string {
+	tpl := components.VersionNumber() + "\n\n"
+	tpl += styling.ColorFg(fmt.Sprintf(config.Currenti18n.LoginTitle, config.Current.JSONRPCEndpointURL), styling.SecondaryColor) + "\n"
+	tpl += styling.Subtle(config.Currenti18n.LoginHint) + "\n\n"
+	tpl += styling.ColorFg(config.Currenti18n.Username, styling.SecondaryColor) + "\n"
+	tpl += m.LoginState.Inputs[LoginUsernameInput].View() + "\n\n"
+	tpl += styling.ColorFg(config.Currenti18n.Password, styling.SecondaryColor) + "\n"
+	tpl += m.LoginState.Inputs[LoginPasswordInput].View() + "\n\n"
+	if m.LoginState.InFlight {
+		tpl += config.Currenti18n.LoggingIn + "\n\n"
+	} else if m.LoginState.Error != nil {
+		tpl += styling.ColorFg(fmt.Sprintf(config.Currenti18n.ErrorBanner, m.LoginState.Error), styling.ErrorColor) + "\n\n"
+	}
+	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.SelectReducedKeybind, config.Currenti18n.Keybinds.LogInKeybind, config.Currenti18n.Keybinds.QuitEscKeybind})
+	return tpl
+}
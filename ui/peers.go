@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/http"
+	"osprey/ui/styling"
+
+	tea "github.com/charmbracelet/bubbletea"
+	humanize "github.com/dustin/go-humanize"
+)
+
+const peerRowFormat = "%-40s %-24s %-11s %-11s %-11s %s"
+
+func updatePeersTab(msg tea.KeyMsg, m Model) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "o":
+		m.DetailsState.PeerSort.By = nextPeerSortField(m.DetailsState.PeerSort.By)
+		torrents.SortPeers(m.DetailsState.Details.Peers, m.DetailsState.PeerSort)
+	case "O":
+		m.DetailsState.PeerSort.Descending = !m.DetailsState.PeerSort.Descending
+		torrents.SortPeers(m.DetailsState.Details.Peers, m.DetailsState.PeerSort)
+	case "a":
+		return m, openDetailsInput(&m, detailsAddPeerInput, config.Currenti18n.AddPeer+" ", "ip:port")
+	}
+	return m, nil
+}
+
+// nextPeerSortField goes through torrents.PeerSortFields and back to the
+// order of Porla, like cycleSort.
+func nextPeerSortField(field string) string {
+	if field == "" {
+		return torrents.PeerSortFields[0]
+	}
+	for i, f := range torrents.PeerSortFields {
+		if f == field && i+1 < len(torrents.PeerSortFields) {
+			return torrents.PeerSortFields[i+1]
+		}
+	}
+	return ""
+}
+
+func addPeer(m *Model, address string) (tea.Cmd, error) {
+	peer, err := torrents.ParseEndpoint(address)
+	if err != nil {
+		return nil, err
+	}
+	return detailsAction(m, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+		return connection.AddPeer(ctx, torrent, peer)
+	}), nil
+}
+
+// peerColumn marks the column the peers are sorted on with an arrow.
+func peerColumn(m Model, field, name string) string {
+	if m.DetailsState.PeerSort.By != field {
+		return name
+	}
+	if m.DetailsState.PeerSort.Descending {
+		return name + " ↓"
+	}
+	return name + " ↑"
+}
+
+func detailsPeers(m Model) string {
+	header := fmt.Sprintf(peerRowFormat,
+		peerColumn(m, "address", config.Currenti18n.PeerAddress),
+		peerColumn(m, "client", config.Currenti18n.PeerClient),
+		peerColumn(m, "progress", config.Currenti18n.PeerProgress),
+		peerColumn(m, "download_rate", config.Currenti18n.PeerDownloadRate),
+		peerColumn(m, "upload_rate", config.Currenti18n.PeerUploadRate),
+		peerColumn(m, "flags", config.Currenti18n.PeerFlags))
+	var rows []string
+	for _, peer := range m.DetailsState.Details.Peers {
+		rows = append(rows, fmt.Sprintf(peerRowFormat, peer.Endpoint, peer.Client, fmt.Sprintf("%.1f%%", peer.Progress*100), humanize.Bytes(peer.DownloadRate)+"/s", humanize.Bytes(peer.UploadRate)+"/s", torrents.PeerFlags(peer)))
+	}
+	tpl := detailsRows(m, header, rows, nil, config.Currenti18n.NoPeers)
+	if len(rows) != 0 {
+		tpl += styling.Subtle(config.Currenti18n.PeerFlagsLegend) + "\n"
+	}
+	return tpl
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/http"
+	"osprey/ui/components"
+	"osprey/ui/styling"
+
+	tea "github.com/charmbracelet/bubbletea"
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Rechecking reads all the data of a torrent from the disk again, past this
+// size it is confirmed first.
+const recheckConfirmSize = 10 * humanize.GByte
+
+// recheckTarget rechecks the target torrents, once confirmed when one of them
+// is large.
+func recheckTarget(m *Model, target TargetTorrent) tea.Cmd {
+	if recheckSize(target) >= recheckConfirmSize {
+		m.Target = target
+		openSubMenu(m, RecheckTorrentIota)
+		return nil
+	}
+	return recheck(target)
+}
+
+func recheck(target TargetTorrent) tea.Cmd {
+	return forEachTorrent(target, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+		return connection.RecheckTorrent(ctx, torrent)
+	})
+}
+
+func recheckSize(target TargetTorrent) uint64 {
+	var size uint64
+	for _, torrent := range target.all() {
+		size += torrent.Size
+	}
+	return size
+}
+
+func reannounceTarget(target TargetTorrent) tea.Cmd {
+	return forEachTorrent(target, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+		return connection.ReannounceTorrent(ctx, torrent)
+	})
+}
+
+// queueTarget moves the target torrents in the download queue. Torrents that
+// aren't queued, with a QueuePosition of -1, or already at the top are left
+// alone, and several torrents are moved in the order that keeps theirs. The
+// cursor then follows the torrent it was on.
+func queueTarget(m *Model, target TargetTorrent, move http.QueueMove) tea.Cmd {
+	var queued []torrents.Torrent
+	for _, torrent := range target.all() {
+		atTop := torrent.QueuePosition == 0 && (move == http.QueueUp || move == http.QueueTop)
+		if torrent.QueuePosition >= 0 && !atTop {
+			queued = append(queued, torrent)
+		}
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+	descending := move == http.QueueDown || move == http.QueueTop
+	sort.Slice(queued, func(i, j int) bool {
+		if descending {
+			return queued[i].QueuePosition > queued[j].QueuePosition
+		}
+		return queued[i].QueuePosition < queued[j].QueuePosition
+	})
+	if len(target.Torrents) == 0 {
+		m.FollowedTorrent = target.Torrent.InfoHash
+	}
+	connection := target.Connection
+	return runAction(func(ctx context.Context) error {
+		// Later moves depend on the earlier ones, so they stop at the first
+		// failure
+		for _, torrent := range queued {
+			if err := connection.MoveInQueue(ctx, torrent, move); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// followTorrent puts the cursor back on the torrent moved in the queue when it
+// is still on the page.
+func followTorrent(m *Model) {
+	if m.FollowedTorrent == (torrents.InfoHash{}) {
+		return
+	}
+	for i, torrent := range m.TorrentList.Torrents {
+		if torrent.InfoHash == m.FollowedTorrent {
+			m.Cursor = i
+		}
+	}
+}
+
+func updateRecheckTorrentView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "y":
+			target := m.Target
+			closeSubMenu(&m)
+			return m, recheck(target)
+		case "n":
+			closeSubMenu(&m)
+		}
+	case tickMsg:
+		return m, tick()
+	}
+	return m, nil
+}
+
+func recheckTorrentView(m Model) string {
+	tpl := styling.ColorFg(targetTitle(m.Target, config.Currenti18n.RecheckingTorrentName, config.Currenti18n.RecheckingTorrents), styling.SecondaryColor) + "\n\n"
+	tpl += fmt.Sprintf(config.Currenti18n.RecheckQuestion, humanize.Bytes(recheckSize(m.Target))) + "\n\n"
+	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.YesKeybind, config.Currenti18n.Keybinds.NoKeybind, config.Currenti18n.Keybinds.EscKeybind})
+	return tpl
+}
@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/http"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SelectionState holds the torrents marked in the torrent list. They are kept
+// by info hash so the selection survives page changes and refreshes.
+type SelectionState struct {
+	Torrents map[torrents.InfoHash]torrents.Torrent
+	// Index on the current page of the last torrent toggled, where V ranges
+	// start. -1 when there is none.
+	Anchor int
+}
+
+type allTorrentsLoadedMsg struct {
+	torrents []torrents.Torrent
+	err      error
+}
+
+func initialSelectionState() SelectionState {
+	return SelectionState{
+		Torrents: map[torrents.InfoHash]torrents.Torrent{},
+		Anchor:   -1,
+	}
+}
+
+func loadAllTorrents(filter torrents.Filter) tea.Cmd {
+	connection := http.Default
+	return func() tea.Msg {
+		all, err := connection.GetAllTorrents(context.Background())
+		var matching []torrents.Torrent
+		for _, torrent := range all {
+			if filter.Match(torrent) {
+				matching = append(matching, torrent)
+			}
+		}
+		return allTorrentsLoadedMsg{torrents: matching, err: err}
+	}
+}
+
+func isSelected(m Model, torrent torrents.Torrent) bool {
+	_, ok := m.SelectionState.Torrents[torrent.InfoHash]
+	return ok
+}
+
+func toggleSelection(m *Model) {
+	torrent := m.TorrentList.Torrents[m.Cursor]
+	if isSelected(*m, torrent) {
+		delete(m.SelectionState.Torrents, torrent.InfoHash)
+	} else {
+		m.SelectionState.Torrents[torrent.InfoHash] = torrent
+	}
+	m.SelectionState.Anchor = m.Cursor
+}
+
+// selectRange marks the torrents between the last one toggled and the cursor.
+func selectRange(m *Model) {
+	from, to := m.SelectionState.Anchor, m.Cursor
+	if from < 0 || from >= len(m.TorrentList.Torrents) {
+		from = to
+	}
+	if from > to {
+		from, to = to, from
+	}
+	for _, torrent := range m.TorrentList.Torrents[from : to+1] {
+		m.SelectionState.Torrents[torrent.InfoHash] = torrent
+	}
+	m.SelectionState.Anchor = m.Cursor
+}
+
+// selectAll marks the whole page first, then every torrent matching the
+// filter, and clears the selection once everything is marked.
+func selectAll(m *Model, filter torrents.Filter) tea.Cmd {
+	pageSelected := true
+	for _, torrent := range m.TorrentList.Torrents {
+		if !isSelected(*m, torrent) {
+			pageSelected = false
+			m.SelectionState.Torrents[torrent.InfoHash] = torrent
+		}
+	}
+	switch {
+	case !pageSelected:
+		return nil
+	case len(m.SelectionState.Torrents) < m.TorrentList.TorrentsTotal:
+		return loadAllTorrents(filter)
+	}
+	clearSelection(m)
+	return nil
+}
+
+func clearSelection(m *Model) {
+	m.SelectionState.Torrents = map[torrents.InfoHash]torrents.Torrent{}
+	m.SelectionState.Anchor = -1
+}
+
+// refreshSelection keeps the marked torrents of the page up to date, so bulk
+// actions see their current state.
+func refreshSelection(m *Model) {
+	for _, torrent := range m.TorrentList.Torrents {
+		if isSelected(*m, torrent) {
+			m.SelectionState.Torrents[torrent.InfoHash] = torrent
+		}
+	}
+}
+
+// listTarget returns what actions of the torrent list apply to: the marked
+// torrents when there are some, the one under the cursor otherwise.
+func listTarget(m Model) (TargetTorrent, bool) {
+	if len(m.SelectionState.Torrents) != 0 {
+		selected := make([]torrents.Torrent, 0, len(m.SelectionState.Torrents))
+		for _, torrent := range m.SelectionState.Torrents {
+			selected = append(selected, torrent)
+		}
+		sort.Slice(selected, func(i, j int) bool {
+			return selected[i].Name < selected[j].Name
+		})
+		return TargetTorrent{Connection: http.Default, Torrent: selected[0], Torrents: selected}, true
+	}
+	if len(m.TorrentList.Torrents) == 0 {
+		return TargetTorrent{}, false
+	}
+	return TargetTorrent{Connection: http.Default, Torrent: m.TorrentList.Torrents[m.Cursor]}, true
+}
+
+// all returns the torrents the target stands for.
+func (t TargetTorrent) all() []torrents.Torrent {
+	if len(t.Torrents) != 0 {
+		return t.Torrents
+	}
+	return []torrents.Torrent{t.Torrent}
+}
+
+// forEachTorrent runs action on every torrent of the target, going on after a
+// failure and returning the first error.
+func forEachTorrent(target TargetTorrent, action func(context.Context, *http.Connection, torrents.Torrent) error) tea.Cmd {
+	return runAction(func(ctx context.Context) error {
+		var firstErr error
+		for _, torrent := range target.all() {
+			if err := action(ctx, target.Connection, torrent); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	})
+}
+
+// pauseResumeTarget pauses the target torrents, or resumes them when they are
+// all paused.
+func pauseResumeTarget(target TargetTorrent) tea.Cmd {
+	if len(target.Torrents) == 0 {
+		return runAction(func(ctx context.Context) error {
+			return target.Connection.PauseResumeTorrent(ctx, target.Torrent)
+		})
+	}
+	allPaused := true
+	for _, torrent := range target.Torrents {
+		allPaused = allPaused && torrents.IsPaused(torrent.Flags)
+	}
+	return forEachTorrent(target, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+		if allPaused {
+			return connection.ResumeTorrent(ctx, torrent)
+		}
+		return connection.PauseTorrent(ctx, torrent)
+	})
+}
+
+// commonSavePath returns the save path shared by all the target torrents.
+func commonSavePath(target TargetTorrent) string {
+	all := target.all()
+	for _, torrent := range all[1:] {
+		if torrent.SavePath != all[0].SavePath {
+			return ""
+		}
+	}
+	return all[0].SavePath
+}
+
+// targetTitle formats the title of a submenu with the name of the torrent, or
+// the number of torrents for bulk actions.
+func targetTitle(target TargetTorrent, single, bulk string) string {
+	if len(target.Torrents) > 1 {
+		return fmt.Sprintf(bulk, len(target.Torrents))
+	}
+	return fmt.Sprintf(single, target.Torrent.Name)
+}
+
+func selectionInfo(m Model) string {
+	return fmt.Sprintf(config.Currenti18n.TorrentsMarked, len(m.SelectionState.Torrents))
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"osprey/config"
+	"osprey/data/sys"
+	"osprey/http"
+	"osprey/ui/components"
+	"osprey/ui/styling"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/indent"
+)
+
+const (
+	SetupEndpointInput = iota
+	SetupSecretKeyInput
+)
+
+type setupTestedMsg struct {
+	versions sys.Versions
+	err      error
+}
+
+// SetupModel is the first run wizard, it asks for the endpoint and secret key
+// of the current profile and only writes them once Porla accepted them.
+type SetupModel struct {
+	Inputs   []textinput.Model
+	Cursor   int
+	Testing  bool
+	Error    error
+	Done     bool
+	Versions sys.Versions
+}
+
+func InitialSetupModel() SetupModel {
+	var inputs []textinput.Model = make([]textinput.Model, 2)
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].CharLimit = -1
+		inputs[i].Width = 50
+		inputs[i].Prompt = ""
+	}
+	inputs[SetupEndpointInput].Placeholder = "http://127.0.0.1:1337/api/v1/jsonrpc"
+	inputs[SetupEndpointInput].SetValue(config.Current.JSONRPCEndpointURL)
+	inputs[SetupSecretKeyInput].Placeholder = config.Currenti18n.SecretKeyPlaceHolder
+	inputs[SetupSecretKeyInput].SetValue(config.Current.SecretKey)
+	inputs[SetupSecretKeyInput].EchoMode = textinput.EchoPassword
+
+	m := SetupModel{Inputs: inputs}
+	if config.Current.JSONRPCEndpointURL != "" {
+		m.Cursor = SetupSecretKeyInput
+	}
+	m.Inputs[m.Cursor].Focus()
+	return m
+}
+
+func testConnection(endpoint, secretKey string) tea.Cmd {
+	profile := config.Current
+	profile.JSONRPCEndpointURL = endpoint
+	profile.SecretKey = secretKey
+	return func() tea.Msg {
+		versions, err := http.Connect(profile).GetVersions(context.Background())
+		return setupTestedMsg{versions: versions, err: err}
+	}
+}
+
+func (m SetupModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.Testing && msg.String() != "ctrl+c" {
+			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "shift+tab":
+			if m.Cursor > 0 {
+				m.Cursor--
+			}
+		case "down", "tab":
+			if m.Cursor < len(m.Inputs)-1 {
+				m.Cursor++
+			}
+		case "enter":
+			if m.Cursor < len(m.Inputs)-1 {
+				m.Cursor++
+				break
+			}
+			endpoint := strings.TrimSpace(m.Inputs[SetupEndpointInput].Value())
+			secretKey := strings.TrimSpace(m.Inputs[SetupSecretKeyInput].Value())
+			if err := config.ValidateEndpoint(endpoint); err != nil {
+				m.Error = err
+				m.Cursor = SetupEndpointInput
+				break
+			}
+			if secretKey == "" {
+				m.Error = errors.New(config.Currenti18n.SecretKeyRequired)
+				break
+			}
+			m.Error = nil
+			m.Testing = true
+			return m, testConnection(endpoint, secretKey)
+		}
+		for i := range m.Inputs {
+			m.Inputs[i].Blur()
+		}
+		m.Inputs[m.Cursor].Focus()
+
+	case setupTestedMsg:
+		m.Testing = false
+		if msg.err != nil {
+			m.Error = msg.err
+			return m, nil
+		}
+		endpoint := strings.TrimSpace(m.Inputs[SetupEndpointInput].Value())
+		secretKey := strings.TrimSpace(m.Inputs[SetupSecretKeyInput].Value())
+		config.SetProfileConnection(config.Current.Name, endpoint, secretKey)
+		if err := config.Save(); err != nil {
+			m.Error = err
+			return m, nil
+		}
+		m.Versions = msg.versions
+		m.Done = true
+		return m, tea.Quit
+	}
+
+	var cmds []tea.Cmd = make([]tea.Cmd, len(m.Inputs))
+	for i := range m.Inputs {
+		m.Inputs[i], cmds[i] = m.Inputs[i].Update(msg)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m SetupModel) View() string {
+	if m.Done {
+		return indent.String("\n"+fmt.Sprintf(config.Currenti18n.SetupSaved, m.Versions.Porla.Version, config.ConfigFilePath)+"\n\n", 2)
+	}
+	tpl := components.VersionNumber() + "\n\n"
+	tpl += styling.ColorFg(config.Currenti18n.SetupWelcome, styling.SecondaryColor) + "\n"
+	tpl += styling.Subtle(fmt.Sprintf(config.Currenti18n.SetupHint, config.ConfigFilePath)) + "\n\n"
+	tpl += styling.ColorFg(config.Currenti18n.Endpoint, styling.SecondaryColor) + "\n"
+	tpl += m.Inputs[SetupEndpointInput].View() + "\n\n"
+	tpl += styling.ColorFg(config.Currenti18n.SecretKey, styling.SecondaryColor) + "\n"
+	tpl += m.Inputs[SetupSecretKeyInput].View() + "\n"
+	tpl += styling.Subtle(config.Currenti18n.SecretKeyHint) + "\n\n"
+	if m.Testing {
+		tpl += config.Currenti18n.TestingConnection + "\n\n"
+	} else if m.Error != nil {
+		tpl += styling.ColorFg(fmt.Sprintf(config.Currenti18n.ErrorBanner, connectionErrorReason(m.Error)), styling.ErrorColor) + "\n\n"
+	}
+	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.SelectReducedKeybind, config.Currenti18n.Keybinds.TestAndSaveKeybind, config.Currenti18n.Keybinds.EscKeybind})
+	return indent.String("\n"+tpl+"\n\n", 2)
+}
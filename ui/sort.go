@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+
+	"osprey/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cycleSort moves the torrent list to the next order of config.SortFields,
+// going back to Porla's own after the last one.
+func cycleSort(m *Model) tea.Cmd {
+	sort := config.Config.Sort
+	next := ""
+	for i, field := range config.SortFields {
+		if sort.By == "" {
+			next = field
+			break
+		}
+		if field == sort.By && i+1 < len(config.SortFields) {
+			next = config.SortFields[i+1]
+		}
+	}
+	sort.By = next
+	return setSort(m, sort)
+}
+
+func reverseSort(m *Model) tea.Cmd {
+	sort := config.Config.Sort
+	sort.Descending = !sort.Descending
+	return setSort(m, sort)
+}
+
+// setSort applies and saves an order, starting over from the first page.
+func setSort(m *Model, sort config.SortType) tea.Cmd {
+	if err := config.SaveSort(sort); err != nil {
+		m.Error = err
+	}
+	m.Page = 0
+	return requestTorrentList(m, 0, CursorToTop)
+}
+
+func sortFieldName(field string) string {
+	names := config.Currenti18n.SortFields
+	return map[string]string{
+		"name":           names.Name,
+		"size":           names.Size,
+		"progress":       names.Progress,
+		"download_rate":  names.DownloadRate,
+		"upload_rate":    names.UploadRate,
+		"peers":          names.Peers,
+		"seeds":          names.Seeds,
+		"queue_position": names.QueuePosition,
+		"eta":            names.ETA,
+		"state":          names.State,
+	}[field]
+}
+
+// sortInfo describes the order of the list for its header, empty when it is
+// Porla's own.
+func sortInfo() string {
+	sort := config.Config.Sort
+	if sort.By == "" {
+		return ""
+	}
+	arrow := "↑"
+	if sort.Descending {
+		arrow = "↓"
+	}
+	return fmt.Sprintf(config.Currenti18n.SortedBy, sortFieldName(sort.By)) + " " + arrow
+}
@@ -11,6 +11,8 @@ import (
 const (
 	HighlightedColor = "212"
 	SecondaryColor   = "225"
+	ErrorColor       = "1"
+	OnlineColor      = "2"
 )
 
 const (
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"osprey/config"
+	"osprey/data/torrents"
+	"osprey/http"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const trackerRowFormat = "%-6s %-45s %-7s %-7s %-18s %s"
+
+const trackerURLPlaceHolder = "udp://tracker.example.com:1337/announce"
+
+func updateTrackersTab(msg tea.KeyMsg, m Model) (tea.Model, tea.Cmd) {
+	trackers := m.DetailsState.Details.Trackers
+	switch msg.String() {
+	case "a":
+		return m, openDetailsInput(&m, detailsAddTrackerInput, config.Currenti18n.AddTracker+" ", trackerURLPlaceHolder)
+	case "e":
+		if len(trackers) != 0 {
+			tracker := trackers[m.DetailsState.Cursor]
+			cmd := openDetailsInput(&m, detailsEditTrackerInput, config.Currenti18n.TrackerURL+" ", trackerURLPlaceHolder)
+			m.DetailsState.Input.SetValue(tracker.URL)
+			m.DetailsState.EditedTracker = tracker.URL
+			return m, cmd
+		}
+	case "x":
+		if len(trackers) != 0 {
+			return m, removeTracker(&m, trackers[m.DetailsState.Cursor].URL)
+		}
+	case "R":
+		return m, detailsAction(&m, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+			return connection.ReannounceTorrent(ctx, torrent)
+		})
+	case "S":
+		return m, detailsAction(&m, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+			return connection.ScrapeTorrent(ctx, torrent)
+		})
+	}
+	return m, nil
+}
+
+// New trackers go in a tier of their own, after the others.
+func addTracker(m *Model, url string) (tea.Cmd, error) {
+	url = strings.TrimSpace(url)
+	if err := torrents.CheckTrackerURL(url); err != nil {
+		return nil, err
+	}
+	tier := 0
+	for _, tracker := range m.DetailsState.Details.Trackers {
+		if tracker.Tier >= tier {
+			tier = tracker.Tier + 1
+		}
+	}
+	return detailsAction(m, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+		return connection.AddTracker(ctx, torrent, url, tier)
+	}), nil
+}
+
+func editTracker(m *Model, oldURL, newURL string) (tea.Cmd, error) {
+	newURL = strings.TrimSpace(newURL)
+	if err := torrents.CheckTrackerURL(newURL); err != nil {
+		return nil, err
+	}
+	var trackers []torrents.Tracker
+	for _, tracker := range m.DetailsState.Details.Trackers {
+		if tracker.URL == oldURL {
+			tracker.URL = newURL
+		}
+		trackers = append(trackers, tracker)
+	}
+	return replaceTrackers(m, trackers), nil
+}
+
+func removeTracker(m *Model, url string) tea.Cmd {
+	var trackers []torrents.Tracker
+	for _, tracker := range m.DetailsState.Details.Trackers {
+		if tracker.URL != url {
+			trackers = append(trackers, tracker)
+		}
+	}
+	return replaceTrackers(m, trackers)
+}
+
+func replaceTrackers(m *Model, trackers []torrents.Tracker) tea.Cmd {
+	return detailsAction(m, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+		return connection.ReplaceTrackers(ctx, torrent, trackers)
+	})
+}
+
+func detailsTrackers(m Model) string {
+	header := fmt.Sprintf(trackerRowFormat, config.Currenti18n.TrackerTier, "URL", config.Currenti18n.TrackerSeeds, config.Currenti18n.TrackerPeers, config.Currenti18n.NextAnnounce, config.Currenti18n.TrackerStatus)
+	var rows []string
+	for _, tracker := range m.DetailsState.Details.Trackers {
+		rows = append(rows, fmt.Sprintf(trackerRowFormat, fmt.Sprint(tracker.Tier), tracker.URL, scrapeCount(tracker.Seeds), scrapeCount(tracker.Peers), nextAnnounce(tracker), trackerStatus(tracker)))
+	}
+	return detailsRows(m, header, rows, nil, config.Currenti18n.NoTrackers)
+}
+
+// Trackers that weren't scraped yet report -1.
+func scrapeCount(count int) string {
+	if count < 0 {
+		return "-"
+	}
+	return fmt.Sprint(count)
+}
+
+func nextAnnounce(tracker torrents.Tracker) string {
+	in := time.Until(time.Unix(tracker.NextAnnounce, 0)).Round(time.Second)
+	if tracker.NextAnnounce <= 0 || in <= 0 {
+		return "-"
+	}
+	return in.String()
+}
+
+func trackerStatus(tracker torrents.Tracker) string {
+	switch {
+	case tracker.Updating:
+		return config.Currenti18n.TrackerUpdating
+	case tracker.Message != "":
+		return tracker.Message
+	case tracker.Fails > 0:
+		return fmt.Sprintf(config.Currenti18n.TrackerFails, tracker.Fails)
+	}
+	return config.Currenti18n.TrackerWorking
+}
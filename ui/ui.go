@@ -1,11 +1,15 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
 	"osprey/config"
+	"osprey/data/sessions"
+	"osprey/data/sys"
 	"osprey/data/torrents"
 	"osprey/http"
 	"osprey/ui/components"
@@ -30,6 +34,11 @@ const (
 	RemoveTorrentIota
 	MoveTorrentIota
 	TorrentSettingsIota
+	ProfilePickerIota
+	DashboardIota
+	LoginIota
+	DetailsIota
+	RecheckTorrentIota
 	QuittingIota
 )
 
@@ -84,9 +93,12 @@ func InitialModel() Model {
 		Cursor:         0,
 		SubMenuCursor:  0,
 		SubMenuEntries: 0,
-		CurrentView:    TorrentListIota,
+		CurrentView:    LoadingViewIota,
 		Progress:       0.0,
 		TorrentList:    torrents.TorrentList{},
+		ConnectionState: ConnectionState{
+			ProgressTarget: connectingProgress,
+		},
 		AddTorrentSubMenuState: AddTorrentSubMenuState{
 			AddTorrentTextInputs: addTorrentTextInputs,
 			AddingMagnetLink:     true,
@@ -99,7 +111,11 @@ func InitialModel() Model {
 			TorrentIsSequenciallyDownloading: false,
 			TorrentSettingsTextInputs:        torrentSettingsTextInputs,
 		},
-		NinjaMode: false,
+		LoginState:     initialLoginState(),
+		SelectionState: initialSelectionState(),
+		FilterState:    initialFilterState(),
+		NinjaMode:      false,
+		ReturnView:     TorrentListIota,
 	}
 }
 
@@ -118,6 +134,31 @@ type MoveTorrentSubMenuState struct {
 	MoveTorrentPathTextInput textinput.Model
 }
 
+// The torrent a submenu acts on and the connection to the server it is on
+type TargetTorrent struct {
+	Connection *http.Connection
+	Torrent    torrents.Torrent
+	// Set for bulk actions on the marked torrents, Torrent is then the first
+	// of them
+	Torrents []torrents.Torrent
+}
+
+// Progress of the handshake shown by the loading view
+const (
+	connectingProgress      = 1.0 / 3
+	loadingTorrentsProgress = 2.0 / 3
+	connectedProgress       = 1.0
+)
+
+type ConnectionState struct {
+	Attempt        int
+	Retries        int
+	ProgressTarget float64
+	Error          error
+	RetryAt        time.Time
+	Versions       sys.Versions
+}
+
 type Model struct {
 	Page                        int
 	Cursor                      int
@@ -126,12 +167,29 @@ type Model struct {
 	CurrentView                 int
 	Progress                    float64
 	TorrentList                 torrents.TorrentList
+	Sessions                    []sessions.Session
 	AddTorrentSubMenuState      AddTorrentSubMenuState
 	MoveTorrentSubMenuState     MoveTorrentSubMenuState
 	TorrentSettingsSubMenuState TorrentSettingsSubMenuState
 	NinjaMode                   bool
+	Error                       error
+	ListRequestID               int
+	ListRequestInFlight         bool
+	ConnectionState             ConnectionState
+	Target                      TargetTorrent
+	ReturnView                  int
+	DashboardState              DashboardState
+	LoginState                  LoginState
+	SelectionState              SelectionState
+	FilterState                 FilterState
+	DetailsState                DetailsState
+	// The torrent the cursor follows after a move in the queue, until
+	// another key is pressed
+	FollowedTorrent torrents.InfoHash
 }
 
+const progressStep = 0.02
+
 func tick() tea.Cmd {
 	return tea.Tick(time.Second, func(time.Time) tea.Msg {
 		return tickMsg{}
@@ -147,7 +205,7 @@ func frame() tea.Cmd {
 func (m Model) Init() tea.Cmd {
 	var cmd tea.Cmd
 	cmd = tea.EnterAltScreen
-	return tea.Batch(tick(), cmd)
+	return tea.Batch(tick(), frame(), loadVersions(m.ConnectionState.Attempt), cmd)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -155,12 +213,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		k := msg.String()
 		// Check if is a submenu
-		if utils.Contains([]int{AddTorrentIota, MoveTorrentIota, RemoveTorrentIota, TorrentSettingsIota}, m.CurrentView) {
+		if utils.Contains([]int{AddTorrentIota, MoveTorrentIota, RemoveTorrentIota, TorrentSettingsIota, ProfilePickerIota, LoginIota, RecheckTorrentIota}, m.CurrentView) {
 			switch k {
 			case "ctrl+c":
 				return m, tea.Quit
 			case "esc":
-				m.CurrentView = TorrentListIota
+				// There is nothing to go back to without a valid secret key
+				if m.CurrentView == LoginIota {
+					return m, tea.Quit
+				}
+				closeSubMenu(&m)
+				return m, nil
 			case "up":
 				if m.SubMenuCursor > 0 {
 					m.SubMenuCursor--
@@ -170,23 +233,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.SubMenuCursor++
 				}
 			}
-		} else {
-			if k == "q" || k == "ctrl+c" {
-				return m, tea.Quit
-			}
+		} else if k == "ctrl+c" || k == "q" && !isTyping(m) {
+			return m, tea.Quit
 		}
 	}
 
 	if msg, ok := msg.(tea.WindowSizeMsg); ok {
 		newPageSize := (msg.Height - 11) / 4 // The torrent elements are 4 lines high and there are 11 lines not used for displaying torrents
 		if newPageSize != 0 {
-			config.Config.PageSize = newPageSize
+			config.Current.PageSize = newPageSize
+		}
+	}
+
+	// Results of network calls can arrive while any view is displayed.
+	switch msg := msg.(type) {
+	case torrentListLoadedMsg:
+		return updateTorrentListLoaded(msg, m)
+	case dashboardServerLoadedMsg:
+		return updateDashboardServerLoaded(msg, m)
+	case actionResultMsg:
+		if http.IsUnauthorized(msg.err) && listViewShown(m) == TorrentListIota {
+			openLogin(&m)
+			return m, nil
+		}
+		if msg.err != nil {
+			m.Error = msg.err
 		}
+		if listViewShown(m) == DashboardIota {
+			return m, pollDashboard(&m)
+		}
+		return m, requestTorrentList(&m, m.Page, KeepCursor)
 	}
 
 	// Hand off the message and model to the appropriate update function for the
 	// appropriate view based on the current state.
 	switch m.CurrentView {
+	case LoadingViewIota:
+		return updateLoadingView(msg, m)
 	case TorrentListIota:
 		return updateListView(msg, m)
 	case AddTorrentIota:
@@ -197,21 +280,123 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return updateMoveTorrentView(msg, m)
 	case TorrentSettingsIota:
 		return updateTorrentSettingsView(msg, m)
+	case ProfilePickerIota:
+		return updateProfilePickerView(msg, m)
+	case DashboardIota:
+		return updateDashboardView(msg, m)
+	case LoginIota:
+		return updateLoginView(msg, m)
+	case DetailsIota:
+		return updateDetailsView(msg, m)
+	case RecheckTorrentIota:
+		return updateRecheckTorrentView(msg, m)
 	}
 	return m, nil
 }
 
+// isTyping tells whether keys go to a text input of the list or detail view.
+func isTyping(m Model) bool {
+	return m.CurrentView == TorrentListIota && m.FilterState.Editing ||
+		m.CurrentView == DetailsIota && m.DetailsState.InputFor != detailsNoInput
+}
+
+// Start a new handshake, results of any previous one are ignored from now on.
+func reconnect(m *Model) tea.Cmd {
+	m.CurrentView = LoadingViewIota
+	m.ConnectionState.Attempt++
+	m.ConnectionState.Error = nil
+	m.ListRequestID++
+	m.ListRequestInFlight = false
+	m.Progress = 0
+	m.ConnectionState.ProgressTarget = connectingProgress
+	return tea.Batch(frame(), loadVersions(m.ConnectionState.Attempt))
+}
+
+func connectionFailed(m *Model, err error) tea.Cmd {
+	if http.IsUnauthorized(err) {
+		openLogin(m)
+		return nil
+	}
+	delay := reconnectDelay(m.ConnectionState.Retries)
+	m.ConnectionState.Retries++
+	m.ConnectionState.Error = err
+	m.ConnectionState.RetryAt = time.Now().Add(delay)
+	return reconnectAfter(m.ConnectionState.Attempt, delay)
+}
+
+func updateLoadingView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			if m.ConnectionState.Error != nil {
+				return m, reconnect(&m)
+			}
+		}
+	case versionsLoadedMsg:
+		if msg.attempt != m.ConnectionState.Attempt {
+			break
+		}
+		if msg.err != nil {
+			return m, connectionFailed(&m, msg.err)
+		}
+		m.ConnectionState.Versions = msg.versions
+		m.ConnectionState.ProgressTarget = loadingTorrentsProgress
+		return m, tea.Batch(frame(), requestTorrentList(&m, m.Page, KeepCursor))
+	case reconnectMsg:
+		if msg.attempt == m.ConnectionState.Attempt {
+			return m, reconnect(&m)
+		}
+	case frameMsg:
+		if m.Progress < m.ConnectionState.ProgressTarget {
+			m.Progress = math.Min(m.Progress+progressStep, m.ConnectionState.ProgressTarget)
+			return m, frame()
+		}
+		if m.Progress >= connectedProgress {
+			m.CurrentView = TorrentListIota
+		}
+	case tickMsg:
+		return m, tick()
+	}
+	return m, nil
+}
+
+// Submenus go back to the view they were opened from.
+func openSubMenu(m *Model, view int) {
+	if m.CurrentView == TorrentListIota || m.CurrentView == DashboardIota {
+		m.ReturnView = m.CurrentView
+	}
+	m.CurrentView = view
+}
+
+func closeSubMenu(m *Model) {
+	m.CurrentView = m.ReturnView
+}
+
+// listViewShown returns the list the user is looking at, or will be back to
+// once the current submenu is closed.
+func listViewShown(m Model) int {
+	if m.CurrentView == TorrentListIota || m.CurrentView == DashboardIota {
+		return m.CurrentView
+	}
+	return m.ReturnView
+}
+
 func updateRemoveTorrentView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "y":
-			http.DeleteTorrent(m.TorrentList.Torrents[m.Cursor], true)
-			m.CurrentView = TorrentListIota
-		case "n":
-			http.DeleteTorrent(m.TorrentList.Torrents[m.Cursor], false)
-			m.CurrentView = TorrentListIota
+		case "y", "n":
+			target := m.Target
+			keepData := msg.String() == "y"
+			if len(target.Torrents) != 0 {
+				clearSelection(&m)
+			}
+			closeSubMenu(&m)
+			return m, runAction(func(ctx context.Context) error {
+				return target.Connection.DeleteTorrents(ctx, target.all(), keepData)
+			})
 		}
 	case tickMsg:
 		return m, tick()
@@ -235,10 +420,16 @@ func updateAddTorrentView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
 
 		case "enter":
 			if (m.AddTorrentSubMenuState.AddTorrentTextInputs[AddTorrentMagnetLinkInput].Value() != "") && (m.AddTorrentSubMenuState.AddTorrentTextInputs[AddTorrentSavePathInput].Value() != "") {
-				http.AddTorrent(m.AddTorrentSubMenuState.AddTorrentTextInputs[AddTorrentMagnetLinkInput].Value(), m.AddTorrentSubMenuState.AddTorrentTextInputs[AddTorrentSavePathInput].Value(), m.AddTorrentSubMenuState.AddingMagnetLink)
+				magnetURI := m.AddTorrentSubMenuState.AddTorrentTextInputs[AddTorrentMagnetLinkInput].Value()
+				savePath := m.AddTorrentSubMenuState.AddTorrentTextInputs[AddTorrentSavePathInput].Value()
+				addingMagnetLink := m.AddTorrentSubMenuState.AddingMagnetLink
 				m.AddTorrentSubMenuState.AddTorrentTextInputs[AddTorrentMagnetLinkInput].Reset()
 				m.AddTorrentSubMenuState.AddTorrentTextInputs[AddTorrentSavePathInput].Reset()
-				m.CurrentView = TorrentListIota
+				closeSubMenu(&m)
+				connection := http.Default
+				return m, runAction(func(ctx context.Context) error {
+					return connection.AddTorrent(ctx, magnetURI, savePath, addingMagnetLink)
+				})
 			}
 
 		}
@@ -266,9 +457,13 @@ func updateMoveTorrentView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 
 		case "enter":
-			http.MoveTorrent(m.TorrentList.Torrents[m.Cursor], m.MoveTorrentSubMenuState.MoveTorrentPathTextInput.Value())
+			target := m.Target
+			newPath := m.MoveTorrentSubMenuState.MoveTorrentPathTextInput.Value()
 			m.MoveTorrentSubMenuState.MoveTorrentPathTextInput.Reset()
-			m.CurrentView = TorrentListIota
+			closeSubMenu(&m)
+			return m, forEachTorrent(target, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+				return connection.MoveTorrent(ctx, torrent, newPath)
+			})
 		}
 	case tickMsg:
 		return m, tick()
@@ -291,15 +486,19 @@ func updateTorrentSettingsView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
 				m.TorrentSettingsSubMenuState.TorrentIsSequenciallyDownloading = !m.TorrentSettingsSubMenuState.TorrentIsSequenciallyDownloading
 			}
 		case "enter":
-			http.SetTorrentProperties(m.TorrentList.Torrents[m.Cursor], torrents.TorrentPropertiesSetData{
+			target := m.Target
+			torrentPropertiesSetData := torrents.TorrentPropertiesSetData{
 				IsAutomaticallyManaged:    m.TorrentSettingsSubMenuState.TorrentIsAutomaticallyManaged,
 				IsSequenciallyDownloading: m.TorrentSettingsSubMenuState.TorrentIsSequenciallyDownloading,
 				DownloadLimit:             m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsDownloadLimitInput].Value(),
 				MaxConnections:            m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsMaxConnectionsInput].Value(),
 				MaxUploads:                m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsMaxUploadsInput].Value(),
 				UploadLimit:               m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsUploadLimitInput].Value(),
+			}
+			closeSubMenu(&m)
+			return m, forEachTorrent(target, func(ctx context.Context, connection *http.Connection, torrent torrents.Torrent) error {
+				return connection.SetTorrentProperties(ctx, torrent, torrentPropertiesSetData)
 			})
-			m.CurrentView = TorrentListIota
 		}
 
 		for i := range m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs {
@@ -319,96 +518,268 @@ func updateTorrentSettingsView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-func decrementPage(m *Model) {
+func updateProfilePickerView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			profileNames := config.ProfileNames()
+			if profileNames[m.SubMenuCursor] == config.Current.Name {
+				closeSubMenu(&m)
+				break
+			}
+			return switchProfile(m, profileNames[m.SubMenuCursor])
+		}
+	case tickMsg:
+		return m, tick()
+	}
+	return m, nil
+}
+
+// Everything shown belongs to the previous server, so start over from a fresh
+// model and go through the loading view again.
+func switchProfile(m Model, name string) (tea.Model, tea.Cmd) {
+	previous, previousi18n := config.Current, config.Currenti18n
+	pageSize := config.Current.PageSize
+	if err := config.UseProfile(name); err != nil {
+		m.Error = err
+		closeSubMenu(&m)
+		return m, nil
+	}
+	profile, err := config.ResolveSecretKey(config.Current)
+	if err != nil {
+		config.Current, config.Currenti18n = previous, previousi18n
+		m.Error = err
+		closeSubMenu(&m)
+		return m, nil
+	}
+	config.Current = profile
+	config.Current.PageSize = pageSize
+	http.InitHTTPClient()
+
+	newModel := InitialModel()
+	newModel.NinjaMode = m.NinjaMode
+	newModel.ConnectionState.Attempt = m.ConnectionState.Attempt
+	newModel.ListRequestID = m.ListRequestID
+	return newModel, reconnect(&newModel)
+}
+
+func decrementPage(m *Model) tea.Cmd {
 	if m.Page > 0 {
 		m.Page--
-		m.TorrentList, m.Page = http.UpdateTorrentList(m.Page)
-		m.Cursor = len(m.TorrentList.Torrents) - 1
+		return requestTorrentList(m, m.Page, CursorToBottom)
 	}
+	return nil
 }
 
-func incrementPage(m *Model) {
+func incrementPage(m *Model) tea.Cmd {
 	if m.Page < getPageCount(*m)-1 {
 		m.Page++
-		m.TorrentList, m.Page = http.UpdateTorrentList(m.Page)
+		return requestTorrentList(m, m.Page, CursorToTop)
+	}
+	return nil
+}
+
+// Keep showing the last known list when a refresh fails, the next tick will
+// try again.
+func updateTorrentListLoaded(msg torrentListLoadedMsg, m Model) (tea.Model, tea.Cmd) {
+	if msg.requestID != m.ListRequestID {
+		return m, nil
+	}
+	m.ListRequestInFlight = false
+	var cmds []tea.Cmd
+	if m.CurrentView == LoadingViewIota {
+		if msg.err != nil {
+			return m, connectionFailed(&m, msg.err)
+		}
+		m.ConnectionState.Retries = 0
+		m.ConnectionState.ProgressTarget = connectedProgress
+		cmds = append(cmds, frame())
+	} else if msg.err != nil {
+		if m.CurrentView == TorrentListIota && isConnectionError(msg.err) {
+			return m, reconnect(&m)
+		}
+		m.Error = msg.err
+		return m, nil
+	}
+	if msg.page != m.Page {
+		m.SelectionState.Anchor = -1
+	}
+	m.TorrentList, m.Page, m.Sessions = msg.torrentList, msg.page, msg.sessions
+	refreshSelection(&m)
+	followTorrent(&m)
+	if m.CurrentView == DetailsIota && m.Target.Connection == http.Default {
+		refreshDetailsTorrent(&m, m.TorrentList.Torrents)
+		if msg.properties != nil && msg.selected == m.Target.Torrent.InfoHash {
+			m.DetailsState.Details.Properties = msg.properties
+		}
+	}
+	switch msg.cursor {
+	case CursorToTop:
 		m.Cursor = 0
+	case CursorToBottom:
+		m.Cursor = len(m.TorrentList.Torrents) - 1
+	}
+	if m.Cursor > len(m.TorrentList.Torrents)-1 {
+		m.Cursor = len(m.TorrentList.Torrents) - 1
 	}
+	if m.Cursor < 0 {
+		m.Cursor = 0
+	}
+	if (getPageCount(m)-1 != -1) && (m.Page > getPageCount(m)-1) {
+		cmds = append(cmds, requestTorrentList(&m, getPageCount(m)-1, CursorToBottom))
+	}
+	return m, tea.Batch(cmds...)
 }
 
 func updateListView(msg tea.Msg, m Model) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && m.FilterState.Editing {
+		return updateFilterBar(msg, m)
+	}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.FollowedTorrent = torrents.InfoHash{}
 		switch msg.String() {
+		case "/":
+			return m, openFilter(&m)
+		case "enter":
+			if len(m.TorrentList.Torrents) != 0 {
+				return m, openDetails(&m, TargetTorrent{Connection: http.Default, Torrent: m.TorrentList.Torrents[m.Cursor]})
+			}
 		case "a":
 			m.SubMenuCursor = 0
 			m.SubMenuEntries = len(m.AddTorrentSubMenuState.AddTorrentTextInputs)
-			m.CurrentView = AddTorrentIota
+			openSubMenu(&m, AddTorrentIota)
 		case "r":
-			if len(m.TorrentList.Torrents) != 0 {
-				m.CurrentView = RemoveTorrentIota
+			if target, ok := listTarget(m); ok {
+				m.Target = target
+				openSubMenu(&m, RemoveTorrentIota)
+			}
+		case "esc":
+			switch {
+			case m.Error != nil:
+				m.Error = nil
+			case len(m.SelectionState.Torrents) != 0:
+				clearSelection(&m)
+			case !m.FilterState.Filter.IsEmpty():
+				return m, setFilter(&m, torrents.Filter{})
 			}
 		case "p":
-			http.PauseResumeTorrent(m.TorrentList.Torrents[m.Cursor])
+			if target, ok := listTarget(m); ok {
+				return m, pauseResumeTarget(target)
+			}
+		case " ":
+			if len(m.TorrentList.Torrents) != 0 {
+				toggleSelection(&m)
+			}
+		case "V":
+			if len(m.TorrentList.Torrents) != 0 {
+				selectRange(&m)
+			}
+		case "*":
+			return m, selectAll(&m, m.FilterState.Filter)
+		case "c":
+			if target, ok := listTarget(m); ok {
+				return m, recheckTarget(&m, target)
+			}
+		case "R":
+			if target, ok := listTarget(m); ok {
+				return m, reannounceTarget(target)
+			}
+		case "[", "]", "{", "}":
+			moves := map[string]http.QueueMove{"[": http.QueueUp, "]": http.QueueDown, "{": http.QueueTop, "}": http.QueueBottom}
+			if target, ok := listTarget(m); ok {
+				return m, queueTarget(&m, target, moves[msg.String()])
+			}
+		case "o":
+			return m, cycleSort(&m)
+		case "O":
+			return m, reverseSort(&m)
 		case "up", "k":
 			if m.Cursor > 0 {
 				m.Cursor--
 			} else {
-				decrementPage(&m)
+				return m, decrementPage(&m)
 			}
 		case "down", "j":
 			if m.Cursor < len(m.TorrentList.Torrents)-1 {
 				m.Cursor++
 			} else {
-				incrementPage(&m)
+				return m, incrementPage(&m)
 			}
 		case "left", "g":
-			decrementPage(&m)
+			return m, decrementPage(&m)
 		case "right", "h":
-			incrementPage(&m)
+			return m, incrementPage(&m)
 		case "m":
-			if len(m.TorrentList.Torrents) != 0 {
-				m.MoveTorrentSubMenuState.MoveTorrentPathTextInput.SetValue(m.TorrentList.Torrents[m.Cursor].SavePath)
-				m.CurrentView = MoveTorrentIota
+			if target, ok := listTarget(m); ok {
+				m.Target = target
+				m.MoveTorrentSubMenuState.MoveTorrentPathTextInput.SetValue(commonSavePath(target))
+				openSubMenu(&m, MoveTorrentIota)
 			}
 		case "s":
-			if len(m.TorrentList.Torrents) != 0 {
-				torrentProperties := http.GetTorrentProperties(m.TorrentList.Torrents[m.Cursor])
-				m.TorrentSettingsSubMenuState.TorrentIsAutomaticallyManaged = torrents.IsAutoManaged(uint64(torrentProperties.Flags))
-				m.TorrentSettingsSubMenuState.TorrentIsSequenciallyDownloading = torrents.IsSequenciallyDownloading(uint64(torrentProperties.Flags))
-				m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsDownloadLimitInput].SetValue(strconv.Itoa(torrentProperties.DownloadLimit))
-				m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsUploadLimitInput].SetValue(strconv.Itoa(torrentProperties.UploadLimit))
-				m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsMaxConnectionsInput].SetValue(strconv.Itoa(torrentProperties.MaxConnections))
-				m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsMaxUploadsInput].SetValue(strconv.Itoa(torrentProperties.MaxUploads))
-				for i := range m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs {
-					m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[i].Blur()
-				}
-				m.SubMenuCursor = 0
-				m.SubMenuEntries = 2 + len(m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs)
-				m.CurrentView = TorrentSettingsIota
+			// Bulk settings start from the flags of the first marked torrent,
+			// limits are only sent when typed in
+			if target, ok := listTarget(m); ok {
+				return m, loadTorrentProperties(target)
 			}
 		case "n":
 			m.NinjaMode = !m.NinjaMode
+		case "P":
+			profileNames := config.ProfileNames()
+			if len(profileNames) > 1 {
+				m.SubMenuCursor = 0
+				for i, name := range profileNames {
+					if name == config.Current.Name {
+						m.SubMenuCursor = i
+					}
+				}
+				m.SubMenuEntries = len(profileNames)
+				openSubMenu(&m, ProfilePickerIota)
+			}
+		case "D":
+			if len(config.ProfileNames()) > 1 {
+				m.CurrentView = DashboardIota
+				return m, openDashboard(&m)
+			}
 		}
-	// Get updated info
-	case tickMsg:
-		m.TorrentList, m.Page = http.UpdateTorrentList(m.Page)
-		if (getPageCount(m)-1 != -1) && (m.Page > getPageCount(m)-1) {
-			m.Page = getPageCount(m) - 1
-			m.TorrentList, m.Page = http.UpdateTorrentList(m.Page)
-			m.Cursor = len(m.TorrentList.Torrents) - 1
+	case torrentPropertiesLoadedMsg:
+		if msg.err != nil {
+			m.Error = msg.err
+			break
 		}
-		if m.Page < 0 {
-			m.Page = 0
-			m.TorrentList, m.Page = http.UpdateTorrentList(m.Page)
-			m.Cursor = 0
+		if target, ok := listTarget(m); !ok || target.Torrent.InfoHash != msg.target.Torrent.InfoHash {
+			break
 		}
-		if m.Cursor > len(m.TorrentList.Torrents)-1 {
-			m.Cursor = len(m.TorrentList.Torrents) - 1
+		m.Target = msg.target
+		torrentProperties := msg.torrentProperties
+		m.TorrentSettingsSubMenuState.TorrentIsAutomaticallyManaged = torrents.IsAutoManaged(uint64(torrentProperties.Flags))
+		m.TorrentSettingsSubMenuState.TorrentIsSequenciallyDownloading = torrents.IsSequenciallyDownloading(uint64(torrentProperties.Flags))
+		m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsDownloadLimitInput].SetValue(strconv.Itoa(torrentProperties.DownloadLimit))
+		m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsUploadLimitInput].SetValue(strconv.Itoa(torrentProperties.UploadLimit))
+		m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsMaxConnectionsInput].SetValue(strconv.Itoa(torrentProperties.MaxConnections))
+		m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[TorrentSettingsMaxUploadsInput].SetValue(strconv.Itoa(torrentProperties.MaxUploads))
+		for i := range m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs {
+			// Empty inputs keep the limits of each marked torrent
+			if len(msg.target.Torrents) > 1 {
+				m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[i].SetValue("")
+			}
+			m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs[i].Blur()
 		}
-		if m.Cursor < 0 {
-			m.Cursor = 0
+		m.SubMenuCursor = 0
+		m.SubMenuEntries = 2 + len(m.TorrentSettingsSubMenuState.TorrentSettingsTextInputs)
+		openSubMenu(&m, TorrentSettingsIota)
+	case allTorrentsLoadedMsg:
+		if msg.err != nil {
+			m.Error = msg.err
+			break
 		}
-		return m, tick()
+		for _, torrent := range msg.torrents {
+			m.SelectionState.Torrents[torrent.InfoHash] = torrent
+		}
+	// Get updated info
+	case tickMsg:
+		return m, tea.Batch(tick(), refreshTorrentList(&m))
 	}
 	return m, nil
 }
@@ -429,21 +800,60 @@ func (m Model) View() string {
 		s = moveTorrentView(m)
 	case TorrentSettingsIota:
 		s = torrentSettingsView(m)
+	case ProfilePickerIota:
+		s = profilePickerView(m)
+	case DashboardIota:
+		s = dashboardView(m)
+	case LoginIota:
+		s = loginView(m)
+	case DetailsIota:
+		s = detailsView(m)
+	case RecheckTorrentIota:
+		s = recheckTorrentView(m)
 	case QuittingIota:
 		return "\n  " + config.Currenti18n.SeeYouLater + "\n\n"
 	default:
 		return "\n  " + config.Currenti18n.ErrorNonExistantView + "\n\n"
 	}
+	if m.Error != nil {
+		s = errorBanner(m) + "\n\n" + s
+	}
 	return indent.String("\n"+s+"\n\n", 2)
 }
 
+func errorBanner(m Model) string {
+	tpl := styling.ColorFg(fmt.Sprintf(config.Currenti18n.ErrorBanner, m.Error), styling.ErrorColor) + "\n"
+	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.DismissErrorKeybind})
+	return tpl
+}
+
 func loadingView(m Model) string {
 	tpl := components.VersionNumber() + "\n\n"
-	tpl += "%s\n\n"
-	tpl += config.Currenti18n.ConnectingToPorlaBackend + "\n\n"
+	tpl += components.Progressbar(80, m.Progress) + "\n\n"
+	if m.ConnectionState.Error != nil {
+		tpl += styling.ColorFg(fmt.Sprintf(config.Currenti18n.ConnectionFailed, connectionErrorReason(m.ConnectionState.Error)), styling.ErrorColor) + "\n"
+		retryIn := time.Until(m.ConnectionState.RetryAt).Round(time.Second)
+		if retryIn < 0 {
+			retryIn = 0
+		}
+		tpl += styling.Subtle(fmt.Sprintf(config.Currenti18n.RetryingIn, retryIn, m.ConnectionState.Retries)) + "\n\n"
+		tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.RetryNowKeybind, config.Currenti18n.Keybinds.QKeybind})
+		return tpl
+	}
+	if m.ConnectionState.ProgressTarget >= loadingTorrentsProgress {
+		tpl += fmt.Sprintf(config.Currenti18n.LoadingTorrentsFromPorla, m.ConnectionState.Versions.Porla.Version) + "\n\n"
+	} else {
+		tpl += config.Currenti18n.ConnectingToPorlaBackend + "\n\n"
+	}
 	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.QKeybind})
+	return tpl
+}
 
-	return fmt.Sprintf(tpl, components.Progressbar(80, m.Progress))
+func connectionErrorReason(err error) string {
+	if http.IsUnauthorized(err) {
+		return config.Currenti18n.AuthenticationFailed
+	}
+	return err.Error()
 }
 
 func addTorrentView(m Model) string {
@@ -462,9 +872,7 @@ func addTorrentView(m Model) string {
 }
 
 func removeTorrentView(m Model) string {
-	selectedTorrent := m.TorrentList.Torrents[m.Cursor]
-
-	tpl := fmt.Sprintf(styling.ColorFg(config.Currenti18n.DeletingTorrentName, styling.SecondaryColor)+"\n\n", selectedTorrent.Name)
+	tpl := styling.ColorFg(targetTitle(m.Target, config.Currenti18n.DeletingTorrentName, config.Currenti18n.DeletingTorrents), styling.SecondaryColor) + "\n\n"
 	tpl += config.Currenti18n.KeepDataQuestion + "\n\n"
 	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.YesKeybind, config.Currenti18n.Keybinds.NoKeybind, config.Currenti18n.Keybinds.EscKeybind})
 
@@ -472,8 +880,7 @@ func removeTorrentView(m Model) string {
 }
 
 func moveTorrentView(m Model) string {
-	selectedTorrent := m.TorrentList.Torrents[m.Cursor]
-	tpl := fmt.Sprintf(styling.ColorFg(config.Currenti18n.MovingTorrentName, styling.SecondaryColor)+"\n\n", selectedTorrent.Name)
+	tpl := styling.ColorFg(targetTitle(m.Target, config.Currenti18n.MovingTorrentName, config.Currenti18n.MovingTorrents), styling.SecondaryColor) + "\n\n"
 	tpl += styling.ColorFg(config.Currenti18n.NewSavePath, styling.SecondaryColor) + "\n"
 	tpl += m.MoveTorrentSubMenuState.MoveTorrentPathTextInput.View() + "\n\n"
 	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.DoneKeybind, config.Currenti18n.Keybinds.EscKeybind})
@@ -482,8 +889,7 @@ func moveTorrentView(m Model) string {
 }
 
 func torrentSettingsView(m Model) string {
-	selectedTorrent := m.TorrentList.Torrents[m.Cursor]
-	tpl := fmt.Sprintf(styling.ColorFg(config.Currenti18n.TorrentSettingsForTorrentName, styling.SecondaryColor)+"\n\n", selectedTorrent.Name)
+	tpl := styling.ColorFg(targetTitle(m.Target, config.Currenti18n.TorrentSettingsForTorrentName, config.Currenti18n.TorrentSettingsForTorrents), styling.SecondaryColor) + "\n\n"
 	tpl += components.Checkbox(config.Currenti18n.AutomaticallyManaged, m.TorrentSettingsSubMenuState.TorrentIsAutomaticallyManaged, m.SubMenuCursor == 0) + "\n"
 	tpl += components.Checkbox(config.Currenti18n.SequentialDownload, m.TorrentSettingsSubMenuState.TorrentIsSequenciallyDownloading, m.SubMenuCursor == 1) + "\n\n"
 	tpl += styling.ColorFg(config.Currenti18n.DownloadLimit, styling.SecondaryColor) + "\n"
@@ -500,18 +906,69 @@ func torrentSettingsView(m Model) string {
 	return fmt.Sprintf(tpl)
 }
 
+func profilePickerView(m Model) string {
+	tpl := styling.ColorFg(config.Currenti18n.SwitchProfile, styling.SecondaryColor) + "\n\n"
+	for i, name := range config.ProfileNames() {
+		profile, _ := config.Profile(name)
+		line := fmt.Sprintf("%s %s", name, styling.Subtle(profile.JSONRPCEndpointURL))
+		if name == config.Current.Name {
+			line += styling.Subtle(" " + config.Currenti18n.CurrentProfile)
+		}
+		if i == m.SubMenuCursor {
+			tpl += styling.ColorFg("> ", styling.HighlightedColor) + styling.ColorFg(line, styling.HighlightedColor) + "\n"
+		} else {
+			tpl += "  " + line + "\n"
+		}
+	}
+	tpl += "\n" + components.KeybindsHints([]string{config.Currenti18n.Keybinds.SelectReducedKeybind, config.Currenti18n.Keybinds.DoneKeybind, config.Currenti18n.Keybinds.EscKeybind})
+	return tpl
+}
+
 func listView(m Model) string {
-	tpl := components.VersionNumber() + "\n\n"
-	tpl += config.Currenti18n.TorrentsActive + "\n"
+	header := components.VersionNumber()
+	if len(config.ProfileNames()) > 1 {
+		header += styling.Dot + styling.Subtle(config.Current.Name)
+	}
+	if !m.FilterState.Filter.IsEmpty() {
+		header += styling.Dot + styling.ColorFg(fmt.Sprintf(config.Currenti18n.FilterActive, m.FilterState.Filter.Text), styling.HighlightedColor)
+	}
+	if sort := sortInfo(); sort != "" {
+		header += styling.Dot + styling.Subtle(sort)
+	}
+	for _, session := range m.Sessions {
+		if session.IsPaused {
+			header += styling.Dot + styling.ColorFg(config.Currenti18n.SessionPaused, styling.ErrorColor)
+		} else if !session.IsListening {
+			header += styling.Dot + styling.ColorFg(config.Currenti18n.SessionNotListening, styling.ErrorColor)
+		}
+	}
+	header += "\n\n" + filterBar(m)
+	tpl := config.Currenti18n.TorrentsActive + "\n"
 	for index, torrent := range m.TorrentList.Torrents {
-		tpl += components.Torrent(torrent, index, m.NinjaMode, index == m.Cursor)
+		tpl += components.Torrent(torrent, index, m.NinjaMode, index == m.Cursor, isSelected(m, torrent))
+	}
+	tpl += styling.Subtle(config.Currenti18n.PageInfo)
+	if len(m.SelectionState.Torrents) != 0 {
+		tpl += styling.Dot + styling.ColorFg(selectionInfo(m), styling.HighlightedColor)
+	}
+	tpl += "\n\n"
+	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.SelectKeybind, config.Currenti18n.Keybinds.ChangePageKeybind, config.Currenti18n.Keybinds.DetailsKeybind, config.Currenti18n.Keybinds.PauseResumeKeybind, config.Currenti18n.Keybinds.AddTorrentKeybind}) + "\n"
+	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.RemoveTorrentKeybind, config.Currenti18n.Keybinds.MoveTorrentKeybind, config.Currenti18n.Keybinds.TorrentSettingsKeybind, config.Currenti18n.Keybinds.RecheckReannounceKeybind, config.Currenti18n.Keybinds.QueueKeybind}) + "\n"
+	keybinds := []string{config.Currenti18n.Keybinds.MarkTorrentsKeybind}
+	switch {
+	case len(m.SelectionState.Torrents) != 0:
+		keybinds = append(keybinds, config.Currenti18n.Keybinds.ClearMarksKeybind)
+	case !m.FilterState.Filter.IsEmpty():
+		keybinds = append(keybinds, config.Currenti18n.Keybinds.ClearFilterKeybind)
+	}
+	keybinds = append(keybinds, config.Currenti18n.Keybinds.FilterKeybind, config.Currenti18n.Keybinds.SortKeybind, config.Currenti18n.Keybinds.NinjaModeKeybind)
+	if len(config.ProfileNames()) > 1 {
+		keybinds = append(keybinds, config.Currenti18n.Keybinds.SwitchProfileKeybind, config.Currenti18n.Keybinds.DashboardKeybind)
 	}
-	tpl += styling.Subtle(config.Currenti18n.PageInfo) + "\n\n"
-	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.SelectKeybind, config.Currenti18n.Keybinds.ChangePageKeybind, config.Currenti18n.Keybinds.PauseResumeKeybind, config.Currenti18n.Keybinds.AddTorrentKeybind}) + "\n"
-	tpl += components.KeybindsHints([]string{config.Currenti18n.Keybinds.RemoveTorrentKeybind, config.Currenti18n.Keybinds.MoveTorrentKeybind, config.Currenti18n.Keybinds.TorrentSettingsKeybind, config.Currenti18n.Keybinds.NinjaModeKeybind, config.Currenti18n.Keybinds.QKeybind})
-	return fmt.Sprintf(tpl, english.Plural(m.TorrentList.TorrentsTotal, config.Currenti18n.Torrent, ""), m.Page+1, getPageCount(m), config.Config.PageSize)
+	tpl += components.KeybindsHints(append(keybinds, config.Currenti18n.Keybinds.QKeybind))
+	return header + fmt.Sprintf(tpl, english.Plural(m.TorrentList.TorrentsTotal, config.Currenti18n.Torrent, ""), m.Page+1, getPageCount(m), config.Current.PageSize)
 }
 
 func getPageCount(m Model) int {
-	return (m.TorrentList.TorrentsTotal-1)/config.Config.PageSize + 1
+	return (m.TorrentList.TorrentsTotal-1)/config.Current.PageSize + 1
 }